@@ -0,0 +1,141 @@
+package fake
+
+import (
+	"backend-test/internal/domain"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MusicProvider is an in-memory music catalog for tests and offline demos.
+// Playlists are matched by case-insensitive query, or by their ID; with
+// Generate set, any unknown query or ID gets a synthesized playlist instead of
+// a not found error.
+type MusicProvider struct {
+	Generate bool
+	Err      error
+
+	mu        sync.RWMutex
+	playlists map[string]domain.PlaylistInfo
+	calls     int
+}
+
+func NewMusicProvider() *MusicProvider {
+	return &MusicProvider{
+		playlists: make(map[string]domain.PlaylistInfo),
+	}
+}
+
+func NewDemoMusicProvider() *MusicProvider {
+	provider := NewMusicProvider()
+	provider.Generate = true
+	return provider
+}
+
+func (p *MusicProvider) AddPlaylist(query string, playlist domain.PlaylistInfo) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.playlists[normalizeQuery(query)] = playlist
+}
+
+func (p *MusicProvider) Calls() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.calls
+}
+
+// Status reports the in-memory catalog as always connected.
+func (p *MusicProvider) Status() domain.MusicProviderStatus {
+	return domain.MusicProviderStatus{
+		Provider: "fake",
+		State:    domain.MusicProviderStateReady,
+	}
+}
+
+func (p *MusicProvider) SearchPlaylist(ctx context.Context, query string) (*domain.PlaylistInfo, error) {
+	p.mu.Lock()
+	p.calls++
+	playlist, ok := p.playlists[normalizeQuery(query)]
+	p.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if p.Err != nil {
+		return nil, p.Err
+	}
+
+	if ok {
+		return &playlist, nil
+	}
+
+	if p.Generate {
+		return generatePlaylist(query), nil
+	}
+
+	return nil, domain.NewNotFoundError("playlist not found")
+}
+
+func (p *MusicProvider) GetPlaylist(ctx context.Context, playlistID string) (*domain.PlaylistInfo, error) {
+	p.mu.Lock()
+	p.calls++
+	var playlist domain.PlaylistInfo
+	ok := false
+	for _, candidate := range p.playlists {
+		if candidate.ID == playlistID {
+			playlist, ok = candidate, true
+			break
+		}
+	}
+	p.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if p.Err != nil {
+		return nil, p.Err
+	}
+
+	if ok {
+		return &playlist, nil
+	}
+
+	if p.Generate {
+		playlist := generatePlaylist(playlistID)
+		playlist.ID = playlistID
+		return playlist, nil
+	}
+
+	return nil, domain.NewNotFoundError("playlist '%s' not found", playlistID)
+}
+
+// demoPlaylistTracks is enough tracks for sampling to make a difference.
+const demoPlaylistTracks = 20
+
+func generatePlaylist(query string) *domain.PlaylistInfo {
+	playlist := &domain.PlaylistInfo{
+		Name:   fmt.Sprintf("%s Demo Playlist", query),
+		Tracks: make([]domain.TrackInfo, 0, demoPlaylistTracks),
+	}
+
+	for i := 1; i <= demoPlaylistTracks; i++ {
+		playlist.Tracks = append(playlist.Tracks, domain.TrackInfo{
+			Name:       fmt.Sprintf("%s Track %d", query, i),
+			Artist:     "Demo Artist",
+			Link:       fmt.Sprintf("https://example.com/tracks/%s-%d", strings.ReplaceAll(normalizeQuery(query), " ", "-"), i),
+			Album:      fmt.Sprintf("%s Demo Album", query),
+			DurationMs: 180000 + i*1000,
+		})
+	}
+
+	return playlist
+}
+
+func normalizeQuery(query string) string {
+	return strings.ToLower(strings.TrimSpace(query))
+}
@@ -0,0 +1,117 @@
+package fake
+
+import (
+	"backend-test/internal/domain"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// WeatherProvider is an in-memory weather service for tests and offline
+// demos. Cities are matched by case-insensitive name and coordinates to four
+// decimal places; with Generate set, any unknown location gets a steady
+// synthesized temperature instead of a not found error.
+type WeatherProvider struct {
+	Generate bool
+	Err      error
+
+	mu           sync.RWMutex
+	observations map[string]domain.WeatherObservation
+	calls        int
+}
+
+func NewWeatherProvider() *WeatherProvider {
+	return &WeatherProvider{
+		observations: make(map[string]domain.WeatherObservation),
+	}
+}
+
+func NewDemoWeatherProvider() *WeatherProvider {
+	provider := NewWeatherProvider()
+	provider.Generate = true
+	return provider
+}
+
+// AddObservation registers the observation under its location name and, when
+// set, its coordinates.
+func (p *WeatherProvider) AddObservation(observation domain.WeatherObservation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	location := observation.Location
+	p.observations[cityKey(location.Name)] = observation
+	if location.Lat != 0 || location.Lon != 0 {
+		p.observations[coordinatesKey(location.Lat, location.Lon)] = observation
+	}
+}
+
+func (p *WeatherProvider) Calls() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.calls
+}
+
+func (p *WeatherProvider) CurrentWeather(ctx context.Context, query domain.WeatherQuery) (*domain.WeatherObservation, error) {
+	var key string
+	switch {
+	case query.City != "":
+		key = cityKey(query.City)
+	case query.Lat != nil && query.Lon != nil:
+		key = coordinatesKey(*query.Lat, *query.Lon)
+	default:
+		return nil, domain.NewValidationError("city or lat and lon are required")
+	}
+
+	p.mu.Lock()
+	p.calls++
+	observation, ok := p.observations[key]
+	p.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if p.Err != nil {
+		return nil, p.Err
+	}
+
+	if ok {
+		return &observation, nil
+	}
+
+	if p.Generate {
+		return generateObservation(query, key), nil
+	}
+
+	return nil, domain.NewNotFoundError("location not found")
+}
+
+// generateObservation derives a temperature between -5°C and 35°C from the
+// location, so the same place always gets the same weather.
+func generateObservation(query domain.WeatherQuery, key string) *domain.WeatherObservation {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+
+	observation := &domain.WeatherObservation{
+		Temperature: float64(hash.Sum32()%401)/10 - 5,
+		Location:    domain.WeatherLocation{Name: query.City},
+		ObservedAt:  time.Now().UTC(),
+	}
+	if query.City == "" {
+		observation.Location.Name = fmt.Sprintf("Demo %s", key)
+		observation.Location.Lat = *query.Lat
+		observation.Location.Lon = *query.Lon
+	}
+	return observation
+}
+
+func cityKey(city string) string {
+	return "city:" + normalizeQuery(city)
+}
+
+func coordinatesKey(lat, lon float64) string {
+	return fmt.Sprintf("%.4f,%.4f", lat, lon)
+}
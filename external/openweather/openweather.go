@@ -0,0 +1,147 @@
+package openweather
+
+import (
+	"backend-test/internal/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the public OpenWeather API.
+const DefaultBaseURL = "https://api.openweathermap.org"
+
+type Config struct {
+	APIKey string
+	// BaseURL points at an OpenWeather compatible API; empty uses
+	// DefaultBaseURL.
+	BaseURL        string
+	RequestTimeout time.Duration
+}
+
+// WeatherService reads the current temperature, in Celsius, from the
+// OpenWeather current weather endpoint.
+type WeatherService struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+}
+
+func NewWeatherService(cfg Config) *WeatherService {
+	return newWeatherService(&http.Client{Timeout: cfg.RequestTimeout}, cfg)
+}
+
+func newWeatherService(httpClient *http.Client, cfg Config) *WeatherService {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &WeatherService{
+		httpClient: httpClient,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     cfg.APIKey,
+	}
+}
+
+func (s *WeatherService) Close() error {
+	s.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// currentWeather is the part of the current weather response in use.
+type currentWeather struct {
+	Name  string `json:"name"`
+	Dt    int64  `json:"dt"`
+	Coord struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"coord"`
+	Main struct {
+		Temp *float64 `json:"temp"`
+	} `json:"main"`
+	Sys struct {
+		Country string `json:"country"`
+	} `json:"sys"`
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+func (s *WeatherService) CurrentWeather(ctx context.Context, query domain.WeatherQuery) (*domain.WeatherObservation, error) {
+	params := url.Values{}
+	params.Set("appid", s.apiKey)
+	params.Set("units", "metric")
+	if query.City != "" {
+		params.Set("q", query.City)
+	} else if query.Lat != nil && query.Lon != nil {
+		params.Set("lat", strconv.FormatFloat(*query.Lat, 'f', -1, 64))
+		params.Set("lon", strconv.FormatFloat(*query.Lon, 'f', -1, 64))
+	} else {
+		return nil, domain.NewValidationError("city or lat and lon are required")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/data/2.5/weather?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		// The request URL carries the API key, so it must not reach the logs.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return nil, fmt.Errorf("openweather: %w", urlErr.Err)
+		}
+		return nil, fmt.Errorf("openweather: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, domain.NewNotFoundError("location %s not found", describeQuery(query))
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr errorResponse
+		_ = json.Unmarshal(body, &apiErr)
+		return nil, fmt.Errorf("openweather: HTTP %d: %s", resp.StatusCode, apiErr.Message)
+	}
+
+	var weather currentWeather
+	if err := json.Unmarshal(body, &weather); err != nil {
+		return nil, fmt.Errorf("openweather: invalid response: %w", err)
+	}
+	if weather.Main.Temp == nil {
+		return nil, fmt.Errorf("openweather: response without temperature")
+	}
+
+	observation := &domain.WeatherObservation{
+		Temperature: *weather.Main.Temp,
+		Location: domain.WeatherLocation{
+			Name:    weather.Name,
+			Country: weather.Sys.Country,
+			Lat:     weather.Coord.Lat,
+			Lon:     weather.Coord.Lon,
+		},
+		ObservedAt: time.Unix(weather.Dt, 0).UTC(),
+	}
+	return observation, nil
+}
+
+func describeQuery(query domain.WeatherQuery) string {
+	if query.City != "" {
+		return fmt.Sprintf("'%s'", query.City)
+	}
+	return fmt.Sprintf("%.4f,%.4f", *query.Lat, *query.Lon)
+}
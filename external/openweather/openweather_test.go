@@ -0,0 +1,106 @@
+package openweather
+
+import (
+	"backend-test/internal/domain"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newWeatherTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/data/2.5/weather" || query.Get("units") != "metric" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		if query.Get("appid") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"cod": 401, "message": "Invalid API key"}`)
+			return
+		}
+
+		switch {
+		case query.Get("q") == "Porto Alegre" || (query.Get("lat") == "-30.03" && query.Get("lon") == "-51.23"):
+			fmt.Fprint(w, `{"coord": {"lon": -51.23, "lat": -30.03}, "main": {"temp": 11.4}, "dt": 1751382000,
+				"sys": {"country": "BR"}, "name": "Porto Alegre", "cod": 200}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"cod": "404", "message": "city not found"}`)
+		}
+	}))
+}
+
+func TestWeatherService_CurrentWeather(t *testing.T) {
+	server := newWeatherTestServer(t)
+	defer server.Close()
+
+	lat, lon := -30.03, -51.23
+	expected := domain.WeatherObservation{
+		Temperature: 11.4,
+		Location:    domain.WeatherLocation{Name: "Porto Alegre", Country: "BR", Lat: -30.03, Lon: -51.23},
+		ObservedAt:  time.Unix(1751382000, 0).UTC(),
+	}
+
+	tests := []struct {
+		name        string
+		apiKey      string
+		query       domain.WeatherQuery
+		expectedErr error
+	}{
+		{"by city", "secret", domain.WeatherQuery{City: "Porto Alegre"}, nil},
+		{"by coordinates", "secret", domain.WeatherQuery{Lat: &lat, Lon: &lon}, nil},
+		{"unknown city", "secret", domain.WeatherQuery{City: "Atlantis"}, domain.ErrNotFound},
+		{"no location", "secret", domain.WeatherQuery{}, domain.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newWeatherService(server.Client(), Config{APIKey: tt.apiKey, BaseURL: server.URL + "/"})
+
+			observation, err := service.CurrentWeather(t.Context(), tt.query)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if *observation != expected {
+				t.Errorf("Expected %+v, got %+v", expected, *observation)
+			}
+		})
+	}
+}
+
+func TestWeatherService_CurrentWeatherRejectedKey(t *testing.T) {
+	server := newWeatherTestServer(t)
+	defer server.Close()
+
+	service := newWeatherService(server.Client(), Config{APIKey: "wrong", BaseURL: server.URL})
+
+	_, err := service.CurrentWeather(t.Context(), domain.WeatherQuery{City: "Porto Alegre"})
+	if err == nil || errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected an upstream error, got %v", err)
+	}
+}
+
+func TestWeatherService_CurrentWeatherHidesKeyOnNetworkErrors(t *testing.T) {
+	server := newWeatherTestServer(t)
+	server.Close()
+
+	service := newWeatherService(&http.Client{}, Config{APIKey: "top-secret-key", BaseURL: server.URL})
+
+	_, err := service.CurrentWeather(t.Context(), domain.WeatherQuery{City: "Porto Alegre"})
+	if err == nil {
+		t.Fatal("Expected an error from a closed server")
+	}
+	if strings.Contains(err.Error(), "top-secret-key") {
+		t.Errorf("Expected the API key to be left out of the error, got %v", err)
+	}
+}
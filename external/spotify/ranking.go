@@ -0,0 +1,124 @@
+package spotify
+
+import (
+	"backend-test/internal/domain"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+const (
+	// DefaultPlaylistQuery searches for the beer style name alone.
+	DefaultPlaylistQuery = "{style}"
+	// DefaultPlaylistCandidates is how many search hits are ranked.
+	DefaultPlaylistCandidates = 10
+
+	playlistQueryPlaceholder = "{style}"
+)
+
+// Weights of each signal in a playlist score. A full name match and an
+// allow-listed owner outweigh popularity, so a famous but unrelated playlist
+// does not win.
+const (
+	nameMatchWeight     = 50.0
+	followersWeight     = 3.0
+	trackCountWeight    = 20.0
+	ownerAllowedWeight  = 30.0
+	trackCountSaturates = 50
+)
+
+type playlistCandidate struct {
+	playlist  spotify.SimplePlaylist
+	followers int
+	score     float64
+}
+
+// playlistQuery fills the configured template with the beer style. A template
+// without the placeholder gets the style appended.
+func playlistQuery(template, style string) string {
+	if template == "" {
+		template = DefaultPlaylistQuery
+	}
+	if !strings.Contains(template, playlistQueryPlaceholder) {
+		return strings.TrimSpace(template + " " + style)
+	}
+	return strings.ReplaceAll(template, playlistQueryPlaceholder, style)
+}
+
+// scorePlaylist rates a candidate for a beer style; higher is better.
+func scorePlaylist(candidate playlistCandidate, style string, owners map[string]bool) float64 {
+	score := nameMatchWeight * nameMatch(candidate.playlist.Name, style)
+	score += followersWeight * math.Log10(1+float64(candidate.followers))
+	score += trackCountWeight * float64(min(int(candidate.playlist.Tracks.Total), trackCountSaturates)) / trackCountSaturates
+
+	if owners[strings.ToLower(candidate.playlist.Owner.ID)] {
+		score += ownerAllowedWeight
+	}
+	return score
+}
+
+// nameMatch is the share of the style's words found in the playlist name,
+// ignoring case and accents.
+func nameMatch(playlistName, style string) float64 {
+	words := strings.FieldsFunc(domain.FoldBeerStyleName(style), isWordSeparator)
+	if len(words) == 0 {
+		return 0
+	}
+
+	nameWords := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(domain.FoldBeerStyleName(playlistName), isWordSeparator) {
+		nameWords[word] = true
+	}
+
+	matched := 0
+	for _, word := range words {
+		if nameWords[word] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(words))
+}
+
+func isWordSeparator(r rune) bool {
+	return !('a' <= r && r <= 'z' || '0' <= r && r <= '9' || r > 0x7f)
+}
+
+// rankPlaylists drops empty playlists and orders the rest from best to worst,
+// keeping the search order between equal scores.
+func rankPlaylists(candidates []playlistCandidate, style string, owners map[string]bool) []playlistCandidate {
+	ranked := make([]playlistCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.playlist.ID == "" || candidate.playlist.Tracks.Total == 0 {
+			continue
+		}
+		candidate.score = scorePlaylist(candidate, style, owners)
+		ranked = append(ranked, candidate)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+	return ranked
+}
+
+// playlistInfo describes the chosen playlist, without its tracks.
+func playlistInfo(playlist spotify.SimplePlaylist) domain.PlaylistInfo {
+	info := domain.PlaylistInfo{
+		ID:    string(playlist.ID),
+		Name:  playlist.Name,
+		URL:   playlist.ExternalURLs["spotify"],
+		Owner: playlist.Owner.DisplayName,
+	}
+	if info.URL == "" {
+		info.URL = "https://open.spotify.com/playlist/" + info.ID
+	}
+	if info.Owner == "" {
+		info.Owner = playlist.Owner.ID
+	}
+	if len(playlist.Images) > 0 {
+		info.ImageURL = playlist.Images[0].URL
+	}
+	return info
+}
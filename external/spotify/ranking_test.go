@@ -0,0 +1,118 @@
+package spotify
+
+import (
+	"testing"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestPlaylistQuery(t *testing.T) {
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"", "Märzen"},
+		{"{style} beer vibes", "Märzen beer vibes"},
+		{"cerveja", "cerveja Märzen"},
+	}
+
+	for _, tt := range tests {
+		if query := playlistQuery(tt.template, "Märzen"); query != tt.expected {
+			t.Errorf("Expected %q for template %q, got %q", tt.expected, tt.template, query)
+		}
+	}
+}
+
+func TestRankPlaylists(t *testing.T) {
+	candidate := func(id, name string, tracks int, owner string, followers int) playlistCandidate {
+		return playlistCandidate{
+			playlist: spotify.SimplePlaylist{
+				ID:     spotify.ID(id),
+				Name:   name,
+				Tracks: spotify.PlaylistTracks{Total: spotify.Numeric(tracks)},
+				Owner:  spotify.User{ID: owner},
+			},
+			followers: followers,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		style      string
+		candidates []playlistCandidate
+		owners     map[string]bool
+		expected   []string
+	}{
+		{
+			"name match beats popularity",
+			"Imperial IPA",
+			[]playlistCandidate{
+				candidate("hits", "Today's Top Hits", 50, "spotify", 30000000),
+				candidate("ipa", "Imperial IPA session", 40, "someone", 200),
+				candidate("half", "IPA Nights", 40, "someone", 200),
+			},
+			nil,
+			[]string{"ipa", "half", "hits"},
+		},
+		{
+			"accents and case are ignored",
+			"Märzen",
+			[]playlistCandidate{
+				candidate("other", "Oktoberfest", 50, "someone", 0),
+				candidate("marzen", "MARZEN classics", 50, "someone", 0),
+			},
+			nil,
+			[]string{"marzen", "other"},
+		},
+		{
+			"empty and tiny playlists",
+			"Stout",
+			[]playlistCandidate{
+				candidate("empty", "Stout", 0, "someone", 100000),
+				candidate("tiny", "Stout", 2, "someone", 0),
+				candidate("full", "Stout", 80, "someone", 0),
+			},
+			nil,
+			[]string{"full", "tiny"},
+		},
+		{
+			"allow-listed owner",
+			"Lager",
+			[]playlistCandidate{
+				candidate("random", "Lager", 50, "someone", 1000),
+				candidate("curated", "Lager", 50, "BeerClub", 10),
+			},
+			map[string]bool{"beerclub": true},
+			[]string{"curated", "random"},
+		},
+		{
+			"ties keep search order",
+			"Pilsner",
+			[]playlistCandidate{
+				candidate("first", "Pilsner", 50, "someone", 0),
+				candidate("second", "Pilsner", 50, "someone", 0),
+			},
+			nil,
+			[]string{"first", "second"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranked := rankPlaylists(tt.candidates, tt.style, tt.owners)
+
+			ids := make([]string, len(ranked))
+			for i, candidate := range ranked {
+				ids[i] = string(candidate.playlist.ID)
+			}
+			if len(ids) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.expected[i] {
+					t.Fatalf("Expected %v, got %v", tt.expected, ids)
+				}
+			}
+		})
+	}
+}
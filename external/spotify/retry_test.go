@@ -0,0 +1,119 @@
+package spotify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newRetryTestServer(statuses []int, headers map[string]string) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(calls.Add(1)) - 1
+		status := http.StatusOK
+		if call < len(statuses) {
+			status = statuses[call]
+		}
+		for key, value := range headers {
+			w.Header().Set(key, value)
+		}
+		w.WriteHeader(status)
+	}))
+	return server, &calls
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name           string
+		statuses       []int
+		headers        map[string]string
+		policy         RetryPolicy
+		expectedStatus int
+		expectedCalls  int32
+	}{
+		{
+			name:           "retries 5xx with backoff",
+			statuses:       []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+			policy:         RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  3,
+		},
+		{
+			name:           "honors Retry-After on 429",
+			statuses:       []int{http.StatusTooManyRequests},
+			headers:        map[string]string{"Retry-After": "0"},
+			policy:         RetryPolicy{MaxRetries: 3, BaseDelay: time.Hour, MaxDelay: time.Hour},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  2,
+		},
+		{
+			name:           "gives up when Retry-After exceeds max delay",
+			statuses:       []int{http.StatusTooManyRequests},
+			headers:        map[string]string{"Retry-After": "120"},
+			policy:         RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCalls:  1,
+		},
+		{
+			name:           "stops after max retries",
+			statuses:       []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			policy:         RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+			expectedStatus: http.StatusInternalServerError,
+			expectedCalls:  3,
+		},
+		{
+			name:           "does not retry client errors",
+			statuses:       []int{http.StatusNotFound},
+			policy:         RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+			expectedStatus: http.StatusNotFound,
+			expectedCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := newRetryTestServer(tt.statuses, tt.headers)
+			defer server.Close()
+
+			client := &http.Client{Transport: newRetryTransport(http.DefaultTransport, tt.policy)}
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+
+			if calls.Load() != tt.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tt.expectedCalls, calls.Load())
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 10, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{now.Add(5 * time.Second).Format(http.TimeFormat), 5 * time.Second, true},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		delay, ok := parseRetryAfter(tt.value, now)
+		if ok != tt.ok || delay != tt.expected {
+			t.Errorf("parseRetryAfter(%q) = %s, %v; expected %s, %v", tt.value, delay, ok, tt.expected, tt.ok)
+		}
+	}
+}
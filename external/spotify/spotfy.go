@@ -1,45 +1,335 @@
 package spotify
 
 import (
+	"backend-test/internal/domain"
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/zmb3/spotify/v2"
 	spotifyauth "github.com/zmb3/spotify/v2/auth"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/sync/errgroup"
 )
 
+// playlistPageSize is the largest page the playlist items endpoint serves.
+const playlistPageSize = 100
+
+// followerLookups caps the follower requests a search runs at once.
+const followerLookups = 4
+
+// defaultTokenTimeout bounds token requests when Config.RequestTimeout is not
+// set.
+const defaultTokenTimeout = 10 * time.Second
+
+// DefaultMaxPlaylistItems is used when Config.MaxPlaylistItems is not set.
+const DefaultMaxPlaylistItems = 500
+
+type Config struct {
+	ClientID       string
+	ClientSecret   string
+	RequestTimeout time.Duration
+	Retry          RetryPolicy
+	// MaxPlaylistItems caps how many playlist items are read across pages.
+	MaxPlaylistItems int
+	// Market is an optional ISO 3166-1 country code; with it Spotify reports
+	// which tracks are unavailable there.
+	Market string
+	// PlaylistQuery is the search template; "{style}" is replaced by the
+	// beer style name.
+	PlaylistQuery string
+	// PlaylistCandidates is how many search hits are fetched and ranked.
+	PlaylistCandidates int
+	// PlaylistOwners are Spotify user IDs whose playlists are preferred.
+	PlaylistOwners []string
+}
+
 type SpotifyService struct {
-	client *spotify.Client
+	client             *spotify.Client
+	httpClient         *http.Client
+	requestTimeout     time.Duration
+	maxPlaylistItems   int
+	market             string
+	playlistQuery      string
+	playlistCandidates int
+	playlistOwners     map[string]bool
 }
 
-func NewSpotifyService(clientID, clientSecret string) (*SpotifyService, error) {
-	config := &clientcredentials.Config{
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
+// NewSpotifyService authenticates with the client credentials flow. Tokens
+// are renewed by the oauth2 token source shortly before they expire, and both
+// token and API requests go through the retry policy.
+func NewSpotifyService(ctx context.Context, cfg Config) (*SpotifyService, error) {
+	transport := newRetryTransport(http.DefaultTransport, cfg.Retry)
+
+	credentials := &clientcredentials.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
 		TokenURL:     spotifyauth.TokenURL,
 	}
-	token, err := config.Token(context.Background())
+
+	tokenSource := newTokenSource(credentials, transport, cfg.RequestTimeout)
+
+	if err := validateToken(ctx, tokenSource); err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Transport: &oauth2.Transport{
+			Source: tokenSource,
+			Base:   transport,
+		},
+	}
+	return newSpotifyService(spotify.New(httpClient), httpClient, cfg), nil
+}
+
+func newSpotifyService(client *spotify.Client, httpClient *http.Client, cfg Config) *SpotifyService {
+	maxPlaylistItems := cfg.MaxPlaylistItems
+	if maxPlaylistItems <= 0 {
+		maxPlaylistItems = DefaultMaxPlaylistItems
+	}
+	playlistCandidates := cfg.PlaylistCandidates
+	if playlistCandidates <= 0 {
+		playlistCandidates = DefaultPlaylistCandidates
+	}
+
+	owners := make(map[string]bool, len(cfg.PlaylistOwners))
+	for _, owner := range cfg.PlaylistOwners {
+		owners[strings.ToLower(owner)] = true
+	}
+
+	return &SpotifyService{
+		client:             client,
+		httpClient:         httpClient,
+		requestTimeout:     cfg.RequestTimeout,
+		maxPlaylistItems:   maxPlaylistItems,
+		market:             cfg.Market,
+		playlistQuery:      cfg.PlaylistQuery,
+		playlistCandidates: playlistCandidates,
+		playlistOwners:     owners,
+	}
+}
+
+// newTokenSource fetches tokens with a client bounded by timeout. Refreshes
+// happen inside API calls but never see their context, so without this a
+// stalled token endpoint would block every request.
+func newTokenSource(credentials *clientcredentials.Config, transport http.RoundTripper, timeout time.Duration) oauth2.TokenSource {
+	if timeout <= 0 {
+		timeout = defaultTokenTimeout
+	}
+	tokenClient := &http.Client{Transport: transport, Timeout: timeout}
+	return credentials.TokenSource(context.WithValue(context.Background(), oauth2.HTTPClient, tokenClient))
+}
+
+func validateToken(ctx context.Context, tokenSource oauth2.TokenSource) error {
+	result := make(chan error, 1)
+	go func() {
+		_, err := tokenSource.Token()
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *SpotifyService) Close() error {
+	s.httpClient.CloseIdleConnections()
+	return nil
+}
+
+func (s *SpotifyService) SearchPlaylist(ctx context.Context, query string) (*domain.PlaylistInfo, error) {
+	playlist, err := s.SearchPlaylistByName(ctx, query)
 	if err != nil {
 		return nil, err
 	}
-	httpClient := spotifyauth.New().Client(context.Background(), token)
-	client := spotify.New(httpClient)
-	return &SpotifyService{client: client}, nil
+
+	tracks, filtered, err := s.playlistTracks(ctx, playlist.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := playlistInfo(*playlist)
+	info.Tracks = tracks
+	info.FilteredTracks = filtered
+	return &info, nil
 }
 
-func (s *SpotifyService) SearchPlaylistByName(name string) (*spotify.FullPlaylist, error) {
-	results, err := s.client.Search(context.Background(), name, spotify.SearchTypePlaylist)
+// GetPlaylist loads a playlist pinned by ID, with the same track filtering
+// as a search result.
+func (s *SpotifyService) GetPlaylist(ctx context.Context, playlistID string) (*domain.PlaylistInfo, error) {
+	playlistCtx, cancel := s.withTimeout(ctx)
+	playlist, err := s.client.GetPlaylist(playlistCtx, spotify.ID(playlistID), spotify.Fields("id,name,external_urls,owner(id,display_name),images"))
+	cancel()
+	var spotifyErr spotify.Error
+	if errors.As(err, &spotifyErr) && (spotifyErr.Status == http.StatusNotFound || spotifyErr.Status == http.StatusBadRequest) {
+		return nil, domain.NewNotFoundError("playlist '%s' not found", playlistID)
+	}
 	if err != nil {
 		return nil, err
 	}
-	if results.Playlists == nil || len(results.Playlists.Playlists) == 0 {
-		return nil, fmt.Errorf("playlist not found")
+
+	tracks, filtered, err := s.playlistTracks(ctx, playlist.ID)
+	if err != nil {
+		return nil, err
 	}
-	playlistID := results.Playlists.Playlists[0].ID
-	fullPlaylist, err := s.client.GetPlaylist(context.Background(), playlistID)
+
+	info := playlistInfo(playlist.SimplePlaylist)
+	info.Tracks = tracks
+	info.FilteredTracks = filtered
+	return &info, nil
+}
+
+// SearchPlaylistByName searches with the configured query template and
+// returns the best ranked of the first candidates, judged by how well the
+// name matches the style, followers, track count and owner.
+func (s *SpotifyService) SearchPlaylistByName(ctx context.Context, name string) (*spotify.SimplePlaylist, error) {
+	searchCtx, cancel := s.withTimeout(ctx)
+	results, err := s.client.Search(searchCtx, playlistQuery(s.playlistQuery, name), spotify.SearchTypePlaylist, spotify.Limit(s.playlistCandidates))
+	cancel()
 	if err != nil {
 		return nil, err
 	}
-	return fullPlaylist, nil
+
+	var candidates []playlistCandidate
+	if results.Playlists != nil {
+		for _, playlist := range results.Playlists.Playlists {
+			if playlist.ID != "" {
+				candidates = append(candidates, playlistCandidate{playlist: playlist})
+			}
+		}
+	}
+
+	s.loadFollowers(ctx, candidates)
+
+	ranked := rankPlaylists(candidates, name, s.playlistOwners)
+	if len(ranked) == 0 {
+		return nil, domain.NewNotFoundError("playlist not found")
+	}
+	return &ranked[0].playlist, nil
+}
+
+// loadFollowers fetches follower counts, which search results do not carry,
+// a few at a time so a search does not burst into rate limits. A candidate
+// whose count cannot be read ranks as if it had no followers instead of
+// failing the search.
+func (s *SpotifyService) loadFollowers(ctx context.Context, candidates []playlistCandidate) {
+	var group errgroup.Group
+	group.SetLimit(followerLookups)
+
+	for i := range candidates {
+		candidate := &candidates[i]
+		group.Go(func() error {
+			ctx, cancel := s.withTimeout(ctx)
+			defer cancel()
+
+			playlist, err := s.client.GetPlaylist(ctx, candidate.playlist.ID, spotify.Fields("followers(total)"))
+			if err != nil {
+				log.Printf("service=SpotifyService func=loadFollowers playlist=%s err=%v", candidate.playlist.ID, err)
+				return nil
+			}
+			candidate.followers = int(playlist.Followers.Count)
+			return nil
+		})
+	}
+	_ = group.Wait()
+}
+
+// playlistTracks follows the playlist's item pages until the end or until
+// maxPlaylistItems items were read. Episodes, local files and tracks that are
+// unavailable are left out and counted as filtered.
+func (s *SpotifyService) playlistTracks(ctx context.Context, playlistID spotify.ID) ([]domain.TrackInfo, int, error) {
+	opts := []spotify.RequestOption{spotify.Limit(min(playlistPageSize, s.maxPlaylistItems))}
+	if s.market != "" {
+		opts = append(opts, spotify.Market(s.market))
+	}
+
+	pageCtx, cancel := s.withTimeout(ctx)
+	page, err := s.client.GetPlaylistItems(pageCtx, playlistID, opts...)
+	cancel()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var tracks []domain.TrackInfo
+	filtered, read := 0, 0
+	for {
+		items := page.Items
+		if remaining := s.maxPlaylistItems - read; len(items) > remaining {
+			items = items[:remaining]
+		}
+		read += len(items)
+
+		pageTracks, pageFiltered := convertItems(items)
+		tracks = append(tracks, pageTracks...)
+		filtered += pageFiltered
+
+		if read >= s.maxPlaylistItems {
+			return tracks, filtered, nil
+		}
+
+		pageCtx, cancel := s.withTimeout(ctx)
+		err := s.client.NextPage(pageCtx, page)
+		cancel()
+		if errors.Is(err, spotify.ErrNoMorePages) {
+			return tracks, filtered, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+}
+
+func (s *SpotifyService) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.requestTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.requestTimeout)
+}
+
+// convertItems keeps the playable music tracks and counts the rest.
+func convertItems(items []spotify.PlaylistItem) ([]domain.TrackInfo, int) {
+	tracks := make([]domain.TrackInfo, 0, len(items))
+	filtered := 0
+	for _, item := range items {
+		track := item.Track.Track
+		if track == nil || item.IsLocal || track.ID == "" || track.Name == "" ||
+			(track.IsPlayable != nil && !*track.IsPlayable) {
+			filtered++
+			continue
+		}
+
+		tracks = append(tracks, domain.TrackInfo{
+			Name:       track.Name,
+			Artist:     artistNames(track.Artists),
+			Link:       fmt.Sprintf("https://open.spotify.com/track/%s", track.ID),
+			Album:      track.Album.Name,
+			DurationMs: int(track.Duration),
+			PreviewURL: track.PreviewURL,
+			Explicit:   track.Explicit,
+			Popularity: int(track.Popularity),
+		})
+	}
+	return tracks, filtered
+}
+
+func artistNames(artists []spotify.SimpleArtist) string {
+	names := make([]string, 0, len(artists))
+	for _, artist := range artists {
+		if artist.Name != "" {
+			names = append(names, artist.Name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "Unknown Artist"
+	}
+	return strings.Join(names, ", ")
 }
@@ -0,0 +1,248 @@
+package spotify
+
+import (
+	"backend-test/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+func TestConvertItems(t *testing.T) {
+	playable, unplayable := true, false
+	items := []spotify.PlaylistItem{
+		{Track: spotify.PlaylistItemTrack{Track: &spotify.FullTrack{
+			SimpleTrack: spotify.SimpleTrack{
+				ID:         "abc",
+				Name:       "Under Pressure",
+				Artists:    []spotify.SimpleArtist{{Name: "Queen"}, {Name: "David Bowie"}},
+				Duration:   248000,
+				Explicit:   true,
+				PreviewURL: "https://p.scdn.co/mp3-preview/abc",
+			},
+			Album:      spotify.SimpleAlbum{Name: "Hot Space"},
+			Popularity: 80,
+			IsPlayable: &playable,
+		}}},
+		{Track: spotify.PlaylistItemTrack{Track: &spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{ID: "def", Name: "Untitled"}}}},
+		{Track: spotify.PlaylistItemTrack{Episode: &spotify.EpisodePage{Name: "A podcast"}}},
+		{IsLocal: true, Track: spotify.PlaylistItemTrack{Track: &spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{Name: "Local file"}}}},
+		{Track: spotify.PlaylistItemTrack{Track: &spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{ID: "ghi", Name: "Blocked"}, IsPlayable: &unplayable}}},
+		{},
+	}
+
+	tracks, filtered := convertItems(items)
+
+	expected := []domain.TrackInfo{
+		{
+			Name:       "Under Pressure",
+			Artist:     "Queen, David Bowie",
+			Link:       "https://open.spotify.com/track/abc",
+			Album:      "Hot Space",
+			DurationMs: 248000,
+			PreviewURL: "https://p.scdn.co/mp3-preview/abc",
+			Explicit:   true,
+			Popularity: 80,
+		},
+		{Name: "Untitled", Artist: "Unknown Artist", Link: "https://open.spotify.com/track/def"},
+	}
+
+	if len(tracks) != len(expected) {
+		t.Fatalf("Expected %d tracks, got %+v", len(expected), tracks)
+	}
+	for i := range expected {
+		if tracks[i] != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], tracks[i])
+		}
+	}
+	if filtered != 4 {
+		t.Errorf("Expected 4 filtered items, got %d", filtered)
+	}
+}
+
+// newPlaylistTestServer serves a search where the best hit is not the first
+// one, and that playlist's total items in pages, where every tenth item is a
+// podcast episode.
+func newPlaylistTestServer(t *testing.T, total int) (*httptest.Server, *atomic.Int32) {
+	var pages atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			if r.URL.Query().Get("q") != "lager beer" {
+				t.Errorf("Expected query 'lager beer', got %q", r.URL.Query().Get("q"))
+			}
+			fmt.Fprintf(w, `{"playlists": {"items": [
+				null,
+				{"id": "pl0", "name": "Lager Beer", "tracks": {"total": 0}},
+				{"id": "pl2", "name": "Top Hits", "tracks": {"total": 100}, "owner": {"id": "spotify"}},
+				{"id": "pl1", "name": "Lager Vibes", "tracks": {"total": %d}, "owner": {"id": "beerclub", "display_name": "Beer Club"},
+				 "external_urls": {"spotify": "https://open.spotify.com/playlist/pl1"}, "images": [{"url": "https://i.scdn.co/image/pl1"}]}
+			]}}`, total)
+		case "/playlists/pl0", "/playlists/pl1", "/playlists/pl2":
+			followers := map[string]int{"/playlists/pl0": 50000, "/playlists/pl1": 120, "/playlists/pl2": 1000000}[r.URL.Path]
+			fmt.Fprintf(w, `{"id": "%s", "name": "Lager Vibes", "followers": {"total": %d}}`, strings.TrimPrefix(r.URL.Path, "/playlists/"), followers)
+		case "/playlists/pl1/tracks":
+			pages.Add(1)
+			if r.URL.Query().Get("market") != "BR" {
+				t.Errorf("Expected market BR, got %q", r.URL.Query().Get("market"))
+			}
+
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+			items := []map[string]interface{}{}
+			for i := offset; i < min(offset+limit, total); i++ {
+				itemType := "track"
+				if i%10 == 9 {
+					itemType = "episode"
+				}
+				items = append(items, map[string]interface{}{
+					"track": map[string]interface{}{"type": itemType, "id": fmt.Sprintf("t%d", i), "name": fmt.Sprintf("Song %d", i)},
+				})
+			}
+
+			next := ""
+			if offset+limit < total {
+				next = fmt.Sprintf("%s/playlists/pl1/tracks?market=BR&limit=%d&offset=%d", server.URL, limit, offset+limit)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"items": items, "next": next, "total": total})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"status": 404, "message": "Resource not found"}}`)
+		}
+	}))
+	return server, &pages
+}
+
+func TestSpotifyService_SearchPlaylistFollowsPages(t *testing.T) {
+	tests := []struct {
+		name             string
+		maxItems         int
+		expectedPages    int32
+		expectedTracks   int
+		expectedFiltered int
+	}{
+		{"whole playlist", 500, 3, 225, 25},
+		{"ceiling mid page", 150, 2, 135, 15},
+		{"ceiling below a page", 20, 1, 18, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, pages := newPlaylistTestServer(t, 250)
+			defer server.Close()
+
+			client := spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/"))
+			service := newSpotifyService(client, server.Client(), Config{MaxPlaylistItems: tt.maxItems, Market: "BR", PlaylistQuery: "{style} beer"})
+
+			playlist, err := service.SearchPlaylist(t.Context(), "lager")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			expectedInfo := domain.PlaylistInfo{ID: "pl1", Name: "Lager Vibes", URL: "https://open.spotify.com/playlist/pl1", Owner: "Beer Club", ImageURL: "https://i.scdn.co/image/pl1"}
+			if playlist.ID != expectedInfo.ID || playlist.URL != expectedInfo.URL || playlist.Owner != expectedInfo.Owner || playlist.ImageURL != expectedInfo.ImageURL {
+				t.Errorf("Expected playlist %+v, got %+v", expectedInfo, playlist)
+			}
+			if playlist.Name != "Lager Vibes" || len(playlist.Tracks) != tt.expectedTracks || playlist.FilteredTracks != tt.expectedFiltered {
+				t.Errorf("Expected %d tracks and %d filtered, got %d and %d", tt.expectedTracks, tt.expectedFiltered, len(playlist.Tracks), playlist.FilteredTracks)
+			}
+			if pages.Load() != tt.expectedPages {
+				t.Errorf("Expected %d page requests, got %d", tt.expectedPages, pages.Load())
+			}
+		})
+	}
+}
+
+func TestSpotifyService_GetPlaylist(t *testing.T) {
+	server, pages := newPlaylistTestServer(t, 30)
+	defer server.Close()
+
+	client := spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/"))
+	service := newSpotifyService(client, server.Client(), Config{Market: "BR"})
+
+	playlist, err := service.GetPlaylist(t.Context(), "pl1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if playlist.ID != "pl1" || len(playlist.Tracks) != 27 || playlist.FilteredTracks != 3 || pages.Load() != 1 {
+		t.Errorf("Expected playlist pl1 with 27 tracks and 3 filtered in 1 page, got %+v after %d pages", playlist, pages.Load())
+	}
+
+	if _, err := service.GetPlaylist(t.Context(), "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestSpotifyService_LoadFollowersLimitsConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(w, `{"followers": {"total": 7}}`)
+	}))
+	defer server.Close()
+
+	client := spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/"))
+	service := newSpotifyService(client, server.Client(), Config{})
+
+	candidates := make([]playlistCandidate, 20)
+	for i := range candidates {
+		candidates[i].playlist.ID = spotify.ID(fmt.Sprintf("pl%d", i))
+	}
+	service.loadFollowers(t.Context(), candidates)
+
+	for _, candidate := range candidates {
+		if candidate.followers != 7 {
+			t.Fatalf("Expected 7 followers for every candidate, got %+v", candidate)
+		}
+	}
+	if peak.Load() > followerLookups {
+		t.Errorf("Expected at most %d concurrent requests, got %d", followerLookups, peak.Load())
+	}
+}
+
+func TestNewTokenSource_TimesOutStalledTokenEndpoint(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	credentials := &clientcredentials.Config{ClientID: "id", ClientSecret: "secret", TokenURL: server.URL}
+	tokenSource := newTokenSource(credentials, http.DefaultTransport, 50*time.Millisecond)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := tokenSource.Token()
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		if err == nil {
+			t.Fatal("Expected an error from the stalled token endpoint, got nil")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the token request to time out")
+	}
+}
@@ -0,0 +1,231 @@
+package app
+
+import (
+	"backend-test/external/fake"
+	"backend-test/external/openweather"
+	"backend-test/external/spotify"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/http/controller"
+	"backend-test/internal/http/handler"
+	"backend-test/internal/service"
+	postgres "backend-test/internal/storage/database"
+	"backend-test/internal/storage/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vingarcia/ksql"
+)
+
+type App struct {
+	config          config.Config
+	db              ksql.Provider
+	musicProvider   service.MusicProvider
+	weatherProvider service.WeatherProvider
+	handler         *handler.Handler
+	closers         []func() error
+}
+
+type Option func(*App)
+
+// WithDatabase injects an already opened database. The caller keeps
+// ownership of it, so App.Close will not close it.
+func WithDatabase(db ksql.Provider) Option {
+	return func(a *App) {
+		a.db = db
+	}
+}
+
+func WithMusicProvider(musicProvider service.MusicProvider) Option {
+	return func(a *App) {
+		a.musicProvider = musicProvider
+	}
+}
+
+func WithWeatherProvider(weatherProvider service.WeatherProvider) Option {
+	return func(a *App) {
+		a.weatherProvider = weatherProvider
+	}
+}
+
+func New(ctx context.Context, cfg config.Config, opts ...Option) (*App, error) {
+	a := &App{config: cfg}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	var strategy service.BeerStyleStrategy
+	if cfg.RecommendationStrategy != "" {
+		var err error
+		if strategy, err = service.BeerStyleStrategyByName(cfg.RecommendationStrategy); err != nil {
+			return nil, fmt.Errorf("invalid RECOMMENDATION_STRATEGY %q: %w", cfg.RecommendationStrategy, err)
+		}
+	}
+
+	if a.db == nil {
+		db, err := postgres.Connect(ctx, cfg.DatabaseURL)
+		if err != nil {
+			return nil, err
+		}
+		a.db = db
+		a.closers = append(a.closers, db.Close)
+	}
+
+	if cfg.MigrateOnStart {
+		if err := a.migrate(ctx); err != nil {
+			a.Close()
+			return nil, err
+		}
+	}
+
+	if a.musicProvider == nil {
+		a.musicProvider = a.newMusicProvider()
+	}
+
+	if a.weatherProvider == nil {
+		a.weatherProvider = a.newWeatherProvider()
+	}
+
+	var providerStatus service.MusicProviderStatusProvider
+	if statusProvider, ok := a.musicProvider.(service.MusicProviderStatusProvider); ok {
+		providerStatus = statusProvider
+	}
+
+	var playlistCache service.PlaylistCacheStatsProvider
+	if a.musicProvider != nil && cfg.PlaylistCacheTTL > 0 {
+		cachedProvider := service.NewCachedMusicProvider(a.musicProvider, cfg.PlaylistCacheTTL, cfg.PlaylistCacheStaleTTL)
+		a.musicProvider = cachedProvider
+		playlistCache = cachedProvider
+	}
+
+	beerRepo := repository.NewBeerRepository(a.db, cfg.DBQueryTimeout)
+	updateService := service.NewUpdateService()
+	beerService := service.NewBeerService(beerRepo, updateService)
+	if cfg.SoftDeleteRetention > 0 && cfg.SoftDeletePurgeInterval > 0 {
+		purgeJob := service.NewBeerStylePurgeJob(beerService, cfg.SoftDeleteRetention, cfg.SoftDeletePurgeInterval)
+		purgeJob.Start()
+		a.closers = append(a.closers, purgeJob.Close)
+	}
+	validationService := service.NewValidationService(beerService, updateService)
+	recommendationService := service.NewRecommendationService(beerService, a.musicProvider, a.weatherProvider, strategy)
+
+	a.handler = handler.NewHandler(
+		controller.NewBeerController(beerService, validationService, updateService),
+		controller.NewRecommendationController(recommendationService, validationService),
+		controller.NewStatusController(playlistCache, providerStatus),
+	)
+
+	return a, nil
+}
+
+func (a *App) HandleRequests(router *gin.Engine) {
+	a.handler.HandleRequests(router)
+}
+
+// Close releases resources in reverse order of acquisition: the music
+// and weather provider clients first, then the database pool.
+func (a *App) Close() error {
+	var errs []error
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		if err := a.closers[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *App) migrate(ctx context.Context) error {
+	migrator, err := postgres.NewMigrator(a.db)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	for _, migration := range applied {
+		log.Printf("Applied migration %s", migration)
+	}
+	return nil
+}
+
+// newWeatherProvider returns nil when recommendations by location are turned
+// off or OpenWeather has no API key.
+func (a *App) newWeatherProvider() service.WeatherProvider {
+	switch a.config.WeatherProvider {
+	case config.WeatherProviderFake:
+		log.Println("Using the offline demo weather provider.")
+		return fake.NewDemoWeatherProvider()
+	case config.WeatherProviderOpenWeather:
+		if a.config.OpenWeatherAPIKey == "" {
+			log.Println("Warning: OPENWEATHER_API_KEY not set. Recommendations by location will be disabled.")
+			return nil
+		}
+
+		weatherService := openweather.NewWeatherService(openweather.Config{
+			APIKey:         a.config.OpenWeatherAPIKey,
+			BaseURL:        a.config.OpenWeatherBaseURL,
+			RequestTimeout: a.config.WeatherRequestTimeout,
+		})
+		a.closers = append(a.closers, weatherService.Close)
+		return weatherService
+	}
+	return nil
+}
+
+// newMusicProvider returns nil when no catalog is configured. Spotify is
+// connected by a supervisor in the background, so a bad network or outage at
+// boot does not keep the API from starting.
+func (a *App) newMusicProvider() service.MusicProvider {
+	if a.config.MusicProvider == config.MusicProviderFake {
+		log.Println("Using the offline demo music provider.")
+		return fake.NewDemoMusicProvider()
+	}
+
+	if a.config.SpotifyClientID == "" || a.config.SpotifyClientSecret == "" {
+		log.Println("Warning: Spotify credentials not set. Spotify integration will be disabled.")
+		return nil
+	}
+
+	spotifyConfig := spotify.Config{
+		ClientID:       a.config.SpotifyClientID,
+		ClientSecret:   a.config.SpotifyClientSecret,
+		RequestTimeout: a.config.SpotifyRequestTimeout,
+		Retry: spotify.RetryPolicy{
+			MaxRetries: a.config.SpotifyMaxRetries,
+			BaseDelay:  a.config.SpotifyRetryBaseDelay,
+			MaxDelay:   a.config.SpotifyRetryMaxDelay,
+		},
+		MaxPlaylistItems: a.config.SpotifyMaxPlaylistItems,
+		Market:           a.config.SpotifyMarket,
+
+		PlaylistQuery:      a.config.SpotifyPlaylistQuery,
+		PlaylistCandidates: a.config.SpotifyPlaylistCandidates,
+		PlaylistOwners:     a.config.SpotifyPlaylistOwners,
+	}
+
+	connect := func(ctx context.Context) (service.MusicProvider, func() error, error) {
+		ctx, cancel := context.WithTimeout(ctx, a.config.SpotifyRequestTimeout)
+		defer cancel()
+
+		spotifyService, err := spotify.NewSpotifyService(ctx, spotifyConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		return spotifyService, spotifyService.Close, nil
+	}
+
+	supervisor := service.NewSupervisedMusicProvider(config.MusicProviderSpotify, connect, service.SupervisorConfig{
+		MinDelay:         a.config.SpotifyConnectMinDelay,
+		MaxDelay:         a.config.SpotifyConnectMaxDelay,
+		FailureThreshold: a.config.SpotifyReconnectAfterFailures,
+	})
+	supervisor.Start()
+
+	a.closers = append(a.closers, supervisor.Close)
+	return supervisor
+}
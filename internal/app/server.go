@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+func (a *App) NewHTTPServer(handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:         a.config.ListenAddr(),
+		Handler:      handler,
+		ReadTimeout:  a.config.ReadTimeout,
+		WriteTimeout: a.config.WriteTimeout,
+		IdleTimeout:  a.config.IdleTimeout,
+	}
+}
+
+// Serve listens until ctx is cancelled, then stops accepting connections and
+// waits up to the configured shutdown timeout for in-flight requests.
+func (a *App) Serve(ctx context.Context, handler http.Handler) error {
+	server := a.NewHTTPServer(handler)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("Listening on %s", server.Addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down server, waiting up to %s for in-flight requests", a.config.ShutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.config.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown failed: %w", err)
+	}
+
+	return nil
+}
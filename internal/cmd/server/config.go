@@ -1,19 +1,187 @@
 package config
 
 import (
-	"backend-test/external/spotify"
-	"log"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
-var DATABASE_URL string
+const (
+	MusicProviderSpotify = "spotify"
+	MusicProviderFake    = "fake"
+)
+
+const (
+	WeatherProviderOpenWeather = "openweather"
+	WeatherProviderFake        = "fake"
+	WeatherProviderNone        = "none"
+)
+
+type Config struct {
+	Port            string
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	ShutdownTimeout time.Duration
+
+	DatabaseURL    string
+	DBQueryTimeout time.Duration
+	MigrateOnStart bool
+
+	SoftDeleteRetention     time.Duration
+	SoftDeletePurgeInterval time.Duration
+
+	MusicProvider         string
+	SpotifyClientID       string
+	SpotifyClientSecret   string
+	SpotifyRequestTimeout time.Duration
+	SpotifyMaxRetries     int
+	SpotifyRetryBaseDelay time.Duration
+	SpotifyRetryMaxDelay  time.Duration
+
+	SpotifyMaxPlaylistItems int
+	SpotifyMarket           string
+
+	SpotifyPlaylistQuery      string
+	SpotifyPlaylistCandidates int
+	SpotifyPlaylistOwners     []string
+
+	SpotifyConnectMinDelay        time.Duration
+	SpotifyConnectMaxDelay        time.Duration
+	SpotifyReconnectAfterFailures int
+
+	PlaylistCacheTTL      time.Duration
+	PlaylistCacheStaleTTL time.Duration
 
-func init() {
+	RecommendationStrategy string
+
+	WeatherProvider       string
+	OpenWeatherAPIKey     string
+	OpenWeatherBaseURL    string
+	WeatherRequestTimeout time.Duration
+}
+
+func Load() (Config, error) {
 	_ = godotenv.Load()
 
-	DATABASE_URL = GetDatabaseURL()
+	cfg := Config{
+		Port:                GetPort(),
+		DatabaseURL:         GetDatabaseURL(),
+		MusicProvider:       GetMusicProvider(),
+		SpotifyClientID:     GetSpotifyClientID(),
+		SpotifyClientSecret: GetSpotifyClientSecret(),
+		SpotifyMarket:       strings.ToUpper(os.Getenv("SPOTIFY_MARKET")),
+
+		SpotifyPlaylistQuery:  GetSpotifyPlaylistQuery(),
+		SpotifyPlaylistOwners: getListEnv("SPOTIFY_PLAYLIST_OWNERS"),
+
+		RecommendationStrategy: GetRecommendationStrategy(),
+
+		WeatherProvider:    GetWeatherProvider(),
+		OpenWeatherAPIKey:  os.Getenv("OPENWEATHER_API_KEY"),
+		OpenWeatherBaseURL: os.Getenv("OPENWEATHER_BASE_URL"),
+	}
+
+	if cfg.MusicProvider != MusicProviderSpotify && cfg.MusicProvider != MusicProviderFake {
+		return Config{}, fmt.Errorf("invalid MUSIC_PROVIDER %q: must be %q or %q", cfg.MusicProvider, MusicProviderSpotify, MusicProviderFake)
+	}
+
+	switch cfg.WeatherProvider {
+	case WeatherProviderOpenWeather, WeatherProviderFake, WeatherProviderNone:
+	default:
+		return Config{}, fmt.Errorf("invalid WEATHER_PROVIDER %q: must be %q, %q or %q",
+			cfg.WeatherProvider, WeatherProviderOpenWeather, WeatherProviderFake, WeatherProviderNone)
+	}
+
+	var err error
+	if cfg.ReadTimeout, err = getDurationEnv("HTTP_READ_TIMEOUT", 15*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.WriteTimeout, err = getDurationEnv("HTTP_WRITE_TIMEOUT", 30*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.IdleTimeout, err = getDurationEnv("HTTP_IDLE_TIMEOUT", 60*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.ShutdownTimeout, err = getDurationEnv("SHUTDOWN_TIMEOUT", 20*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.DBQueryTimeout, err = getDurationEnv("DB_QUERY_TIMEOUT", 5*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.MigrateOnStart, err = getBoolEnv("MIGRATE_ON_START", true); err != nil {
+		return Config{}, err
+	}
+	if cfg.SoftDeleteRetention, err = getDurationEnv("SOFT_DELETE_RETENTION", 30*24*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.SoftDeletePurgeInterval, err = getDurationEnv("SOFT_DELETE_PURGE_INTERVAL", time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.SpotifyRequestTimeout, err = getDurationEnv("SPOTIFY_REQUEST_TIMEOUT", 10*time.Second); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.SpotifyMaxRetries, err = getIntEnv("SPOTIFY_MAX_RETRIES", 3); err != nil {
+		return Config{}, err
+	}
+	if cfg.SpotifyRetryBaseDelay, err = getDurationEnv("SPOTIFY_RETRY_BASE_DELAY", 500*time.Millisecond); err != nil {
+		return Config{}, err
+	}
+	if cfg.SpotifyRetryMaxDelay, err = getDurationEnv("SPOTIFY_RETRY_MAX_DELAY", 10*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.SpotifyMaxPlaylistItems, err = getIntEnv("SPOTIFY_MAX_PLAYLIST_ITEMS", 500); err != nil {
+		return Config{}, err
+	}
+	if cfg.SpotifyMaxPlaylistItems < 1 {
+		return Config{}, fmt.Errorf("invalid SPOTIFY_MAX_PLAYLIST_ITEMS %d: must be at least 1", cfg.SpotifyMaxPlaylistItems)
+	}
+	if cfg.SpotifyPlaylistCandidates, err = getIntEnv("SPOTIFY_PLAYLIST_CANDIDATES", 10); err != nil {
+		return Config{}, err
+	}
+	if cfg.SpotifyPlaylistCandidates < 1 || cfg.SpotifyPlaylistCandidates > 50 {
+		return Config{}, fmt.Errorf("invalid SPOTIFY_PLAYLIST_CANDIDATES %d: must be between 1 and 50", cfg.SpotifyPlaylistCandidates)
+	}
+	if cfg.SpotifyConnectMinDelay, err = getDurationEnv("SPOTIFY_CONNECT_MIN_DELAY", time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.SpotifyConnectMaxDelay, err = getDurationEnv("SPOTIFY_CONNECT_MAX_DELAY", time.Minute); err != nil {
+		return Config{}, err
+	}
+	if cfg.SpotifyReconnectAfterFailures, err = getIntEnv("SPOTIFY_RECONNECT_AFTER_FAILURES", 5); err != nil {
+		return Config{}, err
+	}
+	if cfg.PlaylistCacheTTL, err = getDurationEnv("PLAYLIST_CACHE_TTL", time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.PlaylistCacheStaleTTL, err = getDurationEnv("PLAYLIST_CACHE_STALE_TTL", 24*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.WeatherRequestTimeout, err = getDurationEnv("WEATHER_REQUEST_TIMEOUT", 5*time.Second); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+func (c Config) ListenAddr() string {
+	if strings.Contains(c.Port, ":") {
+		return c.Port
+	}
+	return ":" + c.Port
+}
+
+func GetPort() string {
+	port := os.Getenv("PORT")
+	if port == "" {
+		return "1112"
+	}
+	return port
 }
 
 func GetDatabaseURL() string {
@@ -24,6 +192,40 @@ func GetDatabaseURL() string {
 	return dbURL
 }
 
+func GetMusicProvider() string {
+	provider := strings.ToLower(os.Getenv("MUSIC_PROVIDER"))
+	if provider == "" {
+		return MusicProviderSpotify
+	}
+	return provider
+}
+
+func GetRecommendationStrategy() string {
+	strategy := strings.ToLower(os.Getenv("RECOMMENDATION_STRATEGY"))
+	if strategy == "" {
+		return "midpoint"
+	}
+	return strategy
+}
+
+func GetWeatherProvider() string {
+	provider := strings.ToLower(os.Getenv("WEATHER_PROVIDER"))
+	if provider == "" {
+		return WeatherProviderOpenWeather
+	}
+	return provider
+}
+
+// GetSpotifyPlaylistQuery returns the playlist search template, where
+// "{style}" stands for the beer style name.
+func GetSpotifyPlaylistQuery() string {
+	query := strings.TrimSpace(os.Getenv("SPOTIFY_PLAYLIST_QUERY"))
+	if query == "" {
+		return "{style}"
+	}
+	return query
+}
+
 func GetSpotifyClientID() string {
 	return os.Getenv("SPOTIFY_CLIENT_ID")
 }
@@ -32,20 +234,55 @@ func GetSpotifyClientSecret() string {
 	return os.Getenv("SPOTIFY_CLIENT_SECRET")
 }
 
-func InitializeSpotifyService() *spotify.SpotifyService {
-	clientID := GetSpotifyClientID()
-	clientSecret := GetSpotifyClientSecret()
+// getListEnv splits a comma separated variable, dropping empty entries.
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getIntEnv(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer %q for %s: %w", value, key, err)
+	}
+
+	return number, nil
+}
+
+func getBoolEnv(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean %q for %s: %w", value, key, err)
+	}
+
+	return enabled, nil
+}
 
-	if clientID == "" || clientSecret == "" {
-		log.Println("Warning: Spotify credentials not set. Spotify integration will be disabled.")
-		return nil
+func getDurationEnv(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
 	}
 
-	spotifyService, err := spotify.NewSpotifyService(clientID, clientSecret)
+	duration, err := time.ParseDuration(value)
 	if err != nil {
-		log.Printf("Warning: Failed to initialize Spotify service: %v", err)
-		return nil
+		return 0, fmt.Errorf("invalid duration %q for %s: %w", value, key, err)
 	}
 
-	return spotifyService
+	return duration, nil
 }
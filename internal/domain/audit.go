@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	AuditOperationCreate  = "create"
+	AuditOperationUpdate  = "update"
+	AuditOperationDelete  = "delete"
+	AuditOperationRestore = "restore"
+)
+
+// AnonymousActor is recorded when a change is made without an identified
+// actor.
+const AnonymousActor = "anonymous"
+
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type BeerStyleAuditEntry struct {
+	ID            int64                  `json:"id" ksql:"id"`
+	BeerStyleUUID string                 `json:"beer_style_uuid" ksql:"beer_style_uuid"`
+	Operation     string                 `json:"operation" ksql:"operation"`
+	Actor         string                 `json:"actor" ksql:"actor"`
+	Changes       map[string]FieldChange `json:"changes" ksql:"changes,json"`
+	CreatedAt     time.Time              `json:"created_at" ksql:"created_at"`
+}
+
+type BeerStyleHistoryParams struct {
+	BeerStyleUUID string
+	Limit         int
+	// BeforeID continues a previous page, listing older entries only.
+	BeforeID int64
+}
+
+type BeerStyleHistoryPage struct {
+	History []BeerStyleAuditEntry `json:"history"`
+	Paging  Paging                `json:"paging"`
+}
+
+// BeerStyleChanges lists the fields that differ between two versions of a
+// beer style, keyed by their JSON name. A nil before describes a creation.
+func BeerStyleChanges(before, after *BeerStyle) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+
+	if before == nil {
+		changes["name"] = FieldChange{Before: nil, After: after.Name}
+		changes["temp_min"] = FieldChange{Before: nil, After: after.TempMin}
+		changes["temp_max"] = FieldChange{Before: nil, After: after.TempMax}
+		return changes
+	}
+
+	if before.Name != after.Name {
+		changes["name"] = FieldChange{Before: before.Name, After: after.Name}
+	}
+	if before.TempMin != after.TempMin {
+		changes["temp_min"] = FieldChange{Before: before.TempMin, After: after.TempMin}
+	}
+	if before.TempMax != after.TempMax {
+		changes["temp_max"] = FieldChange{Before: before.TempMax, After: after.TempMax}
+	}
+	if !sameTime(before.DeletedAt, after.DeletedAt) {
+		changes["deleted_at"] = FieldChange{Before: before.DeletedAt, After: after.DeletedAt}
+	}
+
+	return changes
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+type actorContextKey struct{}
+
+// WithActor records who is making the changes carried out with ctx.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorContextKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
@@ -1,14 +1,26 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
 
 type BeerStyle struct {
-	UUID      string    `json:"uuid" ksql:"uuid"`
-	Name      string    `json:"name" binding:"required" ksql:"name"`
-	TempMin   float64   `json:"temp_min" ksql:"temp_min"`
-	TempMax   float64   `json:"temp_max" ksql:"temp_max"`
-	CreatedAt time.Time `json:"created_at" ksql:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" ksql:"updated_at"`
+	UUID      string     `json:"uuid" ksql:"uuid"`
+	Name      string     `json:"name" binding:"required" ksql:"name"`
+	TempMin   float64    `json:"temp_min" ksql:"temp_min"`
+	TempMax   float64    `json:"temp_max" ksql:"temp_max"`
+	CreatedAt time.Time  `json:"created_at" ksql:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" ksql:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" ksql:"deleted_at"`
+	// Version is incremented on every write and backs the ETag used for
+	// optimistic concurrency control.
+	Version int64 `json:"version" ksql:"version"`
 }
 
 type BeerStyleUpdateRequest struct {
@@ -16,3 +28,57 @@ type BeerStyleUpdateRequest struct {
 	TempMin *float64 `json:"temp_min,omitempty"`
 	TempMax *float64 `json:"temp_max,omitempty"`
 }
+
+type BeerStyleListParams struct {
+	Limit       int
+	SortBy      string
+	SortDir     string
+	Name        string
+	Temperature *float64
+	After       *BeerStyleCursor
+	// IncludeDeleted also lists soft-deleted styles.
+	IncludeDeleted bool
+}
+
+type BeerStyleCursor struct {
+	SortBy string `json:"s"`
+	Value  string `json:"v"`
+	UUID   string `json:"id"`
+}
+
+type Paging struct {
+	Limit      int    `json:"limit"`
+	Sort       string `json:"sort"`
+	Order      string `json:"order"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+type BeerStylePage struct {
+	BeerStyles []BeerStyle `json:"beerStyles"`
+	Paging     Paging      `json:"paging"`
+}
+
+// unaccentLetters covers the letters that unaccent rewrites but that have no
+// Unicode decomposition, so stripping combining marks leaves them untouched.
+var unaccentLetters = strings.NewReplacer(
+	"ø", "o", "Ø", "O",
+	"ł", "l", "Ł", "L",
+	"đ", "d", "Đ", "D",
+	"ß", "ss",
+	"æ", "ae", "Æ", "AE",
+	"œ", "oe", "Œ", "OE",
+)
+
+// FoldBeerStyleName folds case and accents for fuzzy matching, so "Märzen"
+// and "marzen" or "Øl" and "ol" compare equal. It only approximates the
+// database's beer_style_name_key; uniqueness checks ask the database through
+// BeerStyleNameKeys instead.
+func FoldBeerStyleName(name string) string {
+	name = unaccentLetters.Replace(name)
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name)
+	if err != nil {
+		folded = name
+	}
+	return strings.ToLower(folded)
+}
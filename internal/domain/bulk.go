@@ -0,0 +1,60 @@
+package domain
+
+const (
+	BulkOperationCreate = "create"
+	BulkOperationUpdate = "update"
+	BulkOperationDelete = "delete"
+)
+
+const (
+	// BulkModeAtomic commits every operation or none of them.
+	BulkModeAtomic = "atomic"
+	// BulkModePartial commits the operations that succeed and reports the
+	// ones that fail.
+	BulkModePartial = "partial"
+)
+
+const (
+	BulkItemSucceeded  = "succeeded"
+	BulkItemFailed     = "failed"
+	BulkItemRolledBack = "rolled_back"
+	BulkItemSkipped    = "skipped"
+)
+
+// BeerStyleBulkOperation is one entry of a bulk request. UUID is required by
+// update and delete; Name, TempMin and TempMax by create. Version, when set,
+// must match the stored version for update and delete.
+type BeerStyleBulkOperation struct {
+	Op      string   `json:"op"`
+	UUID    string   `json:"uuid,omitempty"`
+	Name    *string  `json:"name,omitempty"`
+	TempMin *float64 `json:"temp_min,omitempty"`
+	TempMax *float64 `json:"temp_max,omitempty"`
+	Version *int64   `json:"version,omitempty"`
+}
+
+type BeerStyleBulkRequest struct {
+	Mode       string                   `json:"mode"`
+	Operations []BeerStyleBulkOperation `json:"operations"`
+}
+
+// BeerStyleBulkItemResult reports the outcome of the operation at Index.
+// Err holds the failure; Code and Message are filled in by the transport.
+type BeerStyleBulkItemResult struct {
+	Index   int        `json:"index"`
+	Op      string     `json:"op"`
+	UUID    string     `json:"uuid,omitempty"`
+	Status  string     `json:"status"`
+	Code    int        `json:"code,omitempty"`
+	Message string     `json:"message,omitempty"`
+	Data    *BeerStyle `json:"data,omitempty"`
+	Err     error      `json:"-"`
+}
+
+type BeerStyleBulkResult struct {
+	Mode      string                    `json:"mode"`
+	Committed bool                      `json:"committed"`
+	Succeeded int                       `json:"succeeded"`
+	Failed    int                       `json:"failed"`
+	Results   []BeerStyleBulkItemResult `json:"results"`
+}
@@ -0,0 +1,47 @@
+package domain
+
+const (
+	CatalogFormatCSV    = "csv"
+	CatalogFormatJSON   = "json"
+	CatalogFormatNDJSON = "ndjson"
+)
+
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionUnchanged = "unchanged"
+	ImportActionConflict  = "conflict"
+	ImportActionInvalid   = "invalid"
+)
+
+// BeerStyleImportRow is one record read from an import file. Row is the line
+// number for CSV and NDJSON and the 1-based array position for JSON. Err is
+// set when the record itself could not be read.
+type BeerStyleImportRow struct {
+	Row     int
+	Name    string
+	TempMin float64
+	TempMax float64
+	Err     error
+}
+
+// BeerStyleImportItem reports what an import does, or would do, with a row.
+type BeerStyleImportItem struct {
+	Row     int    `json:"row"`
+	Name    string `json:"name"`
+	Action  string `json:"action"`
+	UUID    string `json:"uuid,omitempty"`
+	Message string `json:"message,omitempty"`
+	Err     error  `json:"-"`
+}
+
+type BeerStyleImportReport struct {
+	DryRun    bool                  `json:"dry_run"`
+	Committed bool                  `json:"committed"`
+	Creates   int                   `json:"creates"`
+	Updates   int                   `json:"updates"`
+	Unchanged int                   `json:"unchanged"`
+	Conflicts int                   `json:"conflicts"`
+	Invalid   int                   `json:"invalid"`
+	Items     []BeerStyleImportItem `json:"items"`
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrConflict            = errors.New("conflict")
+	ErrValidation          = errors.New("validation failed")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	ErrPreconditionFailed  = errors.New("precondition failed")
+)
+
+// Error carries a client-safe Message classified by one of the sentinel
+// kinds above, optionally wrapping the underlying cause for logging.
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+func NewNotFoundError(format string, args ...interface{}) error {
+	return &Error{Kind: ErrNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewConflictError(format string, args ...interface{}) error {
+	return &Error{Kind: ErrConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewValidationError(format string, args ...interface{}) error {
+	return &Error{Kind: ErrValidation, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewPreconditionFailedError reports that the caller's expected version of a
+// resource no longer matches the stored one.
+func NewPreconditionFailedError(format string, args ...interface{}) error {
+	return &Error{Kind: ErrPreconditionFailed, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewUpstreamUnavailableError(err error, format string, args ...interface{}) error {
+	return &Error{Kind: ErrUpstreamUnavailable, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// ErrorMessage returns the client-safe message of a domain error, or
+// fallback when err does not carry one.
+func ErrorMessage(err error, fallback string) string {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Message
+	}
+	return fallback
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// BeerStylePlaylist pins a playlist to a beer style. Recommendations try a
+// style's playlists from the highest priority down before searching.
+type BeerStylePlaylist struct {
+	BeerStyleUUID string    `json:"beer_style_uuid" ksql:"beer_style_uuid"`
+	PlaylistID    string    `json:"playlist_id" ksql:"playlist_id"`
+	Priority      int       `json:"priority" ksql:"priority"`
+	CreatedAt     time.Time `json:"created_at" ksql:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" ksql:"updated_at"`
+}
+
+type BeerStylePlaylistRequest struct {
+	PlaylistID string `json:"playlist_id"`
+	Priority   *int   `json:"priority"`
+}
+
+var playlistIDPattern = regexp.MustCompile(`^[A-Za-z0-9]{1,64}$`)
+
+// ParsePlaylistID accepts a bare playlist ID, a spotify:playlist: URI or an
+// open.spotify.com playlist link and returns the bare ID.
+func ParsePlaylistID(raw string) (string, error) {
+	id := strings.TrimSpace(raw)
+
+	switch {
+	case strings.HasPrefix(id, "spotify:playlist:"):
+		id = strings.TrimPrefix(id, "spotify:playlist:")
+	case strings.Contains(id, "/"):
+		link, err := url.Parse(id)
+		if err != nil || link.Host != "open.spotify.com" {
+			return "", NewValidationError("playlist_id must be a playlist ID, URI or open.spotify.com link")
+		}
+		segments := strings.Split(strings.Trim(link.Path, "/"), "/")
+		if len(segments) < 2 || segments[len(segments)-2] != "playlist" {
+			return "", NewValidationError("playlist_id must be a playlist ID, URI or open.spotify.com link")
+		}
+		id = segments[len(segments)-1]
+	}
+
+	if !playlistIDPattern.MatchString(id) {
+		return "", NewValidationError("playlist_id must be a playlist ID, URI or open.spotify.com link")
+	}
+	return id, nil
+}
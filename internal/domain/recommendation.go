@@ -1,22 +1,116 @@
 package domain
 
+import "time"
 
+// TemperatureRequest asks for a recommendation either for a temperature or
+// for the current weather at a city or at lat/lon coordinates.
 type TemperatureRequest struct {
-	Temperature float64 `json:"temperature"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	City        string   `json:"city,omitempty"`
+	Lat         *float64 `json:"lat,omitempty"`
+	Lon         *float64 `json:"lon,omitempty"`
+	Strategy    string   `json:"strategy,omitempty"`
 }
 
+// HasLocation reports whether the request names a city or coordinates.
+func (r TemperatureRequest) HasLocation() bool {
+	return r.City != "" || r.Lat != nil || r.Lon != nil
+}
+
+// WeatherQuery is the location part of the request.
+func (r TemperatureRequest) WeatherQuery() WeatherQuery {
+	return WeatherQuery{City: r.City, Lat: r.Lat, Lon: r.Lon}
+}
+
+// RecommendationOptions tunes a recommendation. Empty fields fall back to the
+// service defaults.
+type RecommendationOptions struct {
+	Strategy string
+	// Limit is how many ranked candidates to return; 0 returns none.
+	Limit int
+	// Tracks is how many playlist tracks to return; 0 uses the default.
+	Tracks   int
+	Sampling string
+	Seed     *int64
+}
+
+// BeerStyleCandidate is a style ranked for a temperature. Lower scores are
+// better; the scale depends on the strategy.
+type BeerStyleCandidate struct {
+	Rank    int     `json:"rank"`
+	UUID    string  `json:"uuid"`
+	Name    string  `json:"name"`
+	TempMin float64 `json:"temp_min"`
+	TempMax float64 `json:"temp_max"`
+	Score   float64 `json:"score"`
+}
+
+const (
+	TrackSamplingFirst  = "first"
+	TrackSamplingRandom = "random"
+	TrackSamplingSeeded = "seeded"
+)
+
+// TrackInfo describes a track. Artist lists every artist, comma separated.
 type TrackInfo struct {
-	Name   string `json:"name"`
-	Artist string `json:"artist"`
-	Link   string `json:"link"`
+	Name       string `json:"name"`
+	Artist     string `json:"artist"`
+	Link       string `json:"link"`
+	Album      string `json:"album,omitempty"`
+	DurationMs int    `json:"duration_ms,omitempty"`
+	PreviewURL string `json:"preview_url,omitempty"`
+	Explicit   bool   `json:"explicit"`
+	Popularity int    `json:"popularity,omitempty"`
 }
 
 type PlaylistInfo struct {
-	Name   string      `json:"name"`
-	Tracks []TrackInfo `json:"tracks"`
+	ID       string      `json:"id,omitempty"`
+	Name     string      `json:"name"`
+	URL      string      `json:"url,omitempty"`
+	Owner    string      `json:"owner,omitempty"`
+	ImageURL string      `json:"image_url,omitempty"`
+	Tracks   []TrackInfo `json:"tracks"`
+	// FilteredTracks counts playlist items left out because they were not
+	// playable music tracks, such as episodes, local files or unavailable
+	// tracks.
+	FilteredTracks int `json:"filtered_tracks,omitempty"`
 }
 
 type RecommendationResponse struct {
-	BeerStyle string       `json:"beerStyle"`
-	Playlist  PlaylistInfo `json:"playlist"`
+	BeerStyle  string               `json:"beerStyle"`
+	Strategy   string               `json:"strategy,omitempty"`
+	Candidates []BeerStyleCandidate `json:"candidates,omitempty"`
+	Playlist   PlaylistInfo         `json:"playlist"`
+	Sampling   string               `json:"sampling,omitempty"`
+	// Seed reproduces a random sample when sent back with sampling=seeded.
+	Seed *int64 `json:"seed,omitempty"`
+	// Weather is the observation the temperature came from, for requests
+	// made by location.
+	Weather *WeatherObservation `json:"weather,omitempty"`
+}
+
+type PlaylistCacheStats struct {
+	Hits         int64 `json:"hits"`
+	StaleHits    int64 `json:"stale_hits"`
+	Misses       int64 `json:"misses"`
+	StaleOnError int64 `json:"stale_on_error"`
+	Evictions    int64 `json:"evictions"`
+	Entries      int   `json:"entries"`
+}
+
+const (
+	MusicProviderStateConnecting   = "connecting"
+	MusicProviderStateReady        = "ready"
+	MusicProviderStateReconnecting = "reconnecting"
+	MusicProviderStateDisabled     = "disabled"
+)
+
+type MusicProviderStatus struct {
+	Provider      string     `json:"provider,omitempty"`
+	State         string     `json:"state"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	ConnectedAt   *time.Time `json:"connected_at,omitempty"`
 }
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// WeatherQuery locates where to read the current weather: a city name, or
+// coordinates.
+type WeatherQuery struct {
+	City string
+	Lat  *float64
+	Lon  *float64
+}
+
+// WeatherLocation is where a temperature was observed, as the weather
+// provider resolved it.
+type WeatherLocation struct {
+	Name    string  `json:"name"`
+	Country string  `json:"country,omitempty"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+}
+
+type WeatherObservation struct {
+	Temperature float64         `json:"temperature"`
+	Location    WeatherLocation `json:"location"`
+	ObservedAt  time.Time       `json:"observed_at"`
+}
@@ -4,13 +4,22 @@ import (
 	"backend-test/internal/domain"
 	"backend-test/internal/service"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
 type BeerController struct {
 	BeerService       service.BeerServiceInterface
 	ValidationService service.ValidationServiceInterface
@@ -26,26 +35,126 @@ func NewBeerController(beerService service.BeerServiceInterface, validationServi
 }
 
 func (bc *BeerController) ListAllBeerStyles(c *gin.Context) {
-	beerStyles, err := bc.BeerService.ListAllBeerStyles()
+	params, err := parseBeerStyleListParams(c)
+	if err == nil {
+		err = bc.ValidationService.ValidateListParams(params)
+	}
 	if err != nil {
 		log.Printf("controller=BeerController func=ListAllBeerStyles err=%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	page, err := bc.BeerService.ListBeerStyles(c.Request.Context(), params)
+	if err != nil {
+		log.Printf("controller=BeerController func=ListAllBeerStyles err=%v", err)
+
+		respondError(c, err, "internal error")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
 
-		status := http.StatusInternalServerError
-		message := "internal error"
+func parseBeerStyleListParams(c *gin.Context) (domain.BeerStyleListParams, error) {
+	params := domain.BeerStyleListParams{
+		Limit:   service.DefaultBeerStyleListLimit,
+		SortBy:  strings.ToLower(c.DefaultQuery("sort", service.SortByName)),
+		SortDir: strings.ToLower(c.DefaultQuery("order", service.SortAsc)),
+		Name:    strings.TrimSpace(c.Query("name")),
+	}
 
-		if bc.ValidationService.IsNoRowsError(err) {
-			status = http.StatusNotFound
-			message = "no beer styles found"
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return params, domain.NewValidationError("limit must be an integer")
 		}
+		params.Limit = limit
+	}
 
-		c.AbortWithStatusJSON(status, gin.H{
-			"message": message,
+	if raw := c.Query("temperature"); raw != "" {
+		temperature, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return params, domain.NewValidationError("temperature must be a number")
+		}
+		params.Temperature = &temperature
+	}
+
+	includeDeleted, err := parseBoolQuery(c, "include_deleted")
+	if err != nil {
+		return params, err
+	}
+	params.IncludeDeleted = includeDeleted
+
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := service.DecodeBeerStyleCursor(raw)
+		if err != nil {
+			return params, err
+		}
+		params.After = cursor
+	}
+
+	return params, nil
+}
+
+func parseBoolQuery(c *gin.Context, name string) (bool, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return false, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, domain.NewValidationError("%s must be a boolean", name)
+	}
+	return value, nil
+}
+
+func (bc *BeerController) GetBeerStyle(c *gin.Context) {
+	beerUUID := c.Param("beerUUID")
+	if err := bc.ValidationService.ValidateUUID(beerUUID); err != nil {
+		log.Printf("controller=BeerController func=GetBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
 		})
 		return
 	}
 
+	includeDeleted, err := parseBoolQuery(c, "include_deleted")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var beerStyle domain.BeerStyle
+	if includeDeleted {
+		beerStyle, err = bc.BeerService.GetBeerStyleByUUIDIncludingDeleted(c.Request.Context(), beerUUID)
+	} else {
+		beerStyle, err = bc.BeerService.GetBeerStyleByUUID(c.Request.Context(), beerUUID)
+	}
+	if err != nil {
+		log.Printf("controller=BeerController func=GetBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		respondError(c, err, "internal error")
+		return
+	}
+
+	etag := beerStyleETag(beerStyle)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Pragma", "")
+	c.Header("Expires", "")
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.AbortWithStatus(http.StatusNotModified)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"beerStyles": beerStyles,
+		"data": beerStyle,
 	})
 }
 
@@ -96,22 +205,6 @@ func (bc *BeerController) CreateBeerStyle(c *gin.Context) {
 		return
 	}
 
-	if err := bc.ValidationService.ValidateUniqueNameForCreate(inputStyle.Name); err != nil {
-		log.Printf("controller=BeerController func=CreateBeerStyle name=%s err=%v", inputStyle.Name, err)
-
-		if strings.Contains(err.Error(), "already exists") {
-			c.JSON(http.StatusConflict, gin.H{
-				"message": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": "failed to validate beer style name",
-		})
-		return
-	}
-
 	if err := bc.ValidationService.ValidateTemperatureRange(inputStyle); err != nil {
 		log.Printf("controller=BeerController func=CreateBeerStyle name=%s err=%v", inputStyle.Name, err)
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -120,12 +213,10 @@ func (bc *BeerController) CreateBeerStyle(c *gin.Context) {
 		return
 	}
 
-	newBeerStyle, err := bc.BeerService.CreateBeerStyle(inputStyle)
+	newBeerStyle, err := bc.BeerService.CreateBeerStyle(c.Request.Context(), inputStyle)
 	if err != nil {
 		log.Printf("controller=BeerController func=CreateBeerStyle name=%s err=%v", inputStyle.Name, err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"message": "failed to create beer style",
-		})
+		respondError(c, err, "failed to create beer style")
 		return
 	}
 
@@ -136,9 +227,18 @@ func (bc *BeerController) CreateBeerStyle(c *gin.Context) {
 
 func (bc *BeerController) UpdateBeerStyle(c *gin.Context) {
 	beerUUID := c.Param("beerUUID")
-	if beerUUID == "" {
+	if err := bc.ValidationService.ValidateUUID(beerUUID); err != nil {
+		log.Printf("controller=BeerController func=UpdateBeerStyle beerUUID=%s err=%v", beerUUID, err)
 		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "beerUUID is required",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ifMatch := c.GetHeader("If-Match")
+	if strings.TrimSpace(ifMatch) == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{
+			"message": "If-Match header is required",
 		})
 		return
 	}
@@ -152,39 +252,16 @@ func (bc *BeerController) UpdateBeerStyle(c *gin.Context) {
 		return
 	}
 
-	currentBeerStyle, err := bc.BeerService.GetBeerStyleByUUID(beerUUID)
+	currentBeerStyle, err := bc.BeerService.GetBeerStyleByUUID(c.Request.Context(), beerUUID)
 	if err != nil {
 		log.Printf("controller=BeerController func=UpdateBeerStyle beerUUID=%s err=%v", beerUUID, err)
-		status := http.StatusInternalServerError
-		message := "internal error"
-
-		if bc.ValidationService.IsNoRowsError(err) {
-			status = http.StatusNotFound
-			message = "beer style not found"
-		}
-
-		c.AbortWithStatusJSON(status, gin.H{
-			"message": message,
-		})
+		respondError(c, err, "internal error")
 		return
 	}
 
-	if updateRequest.Name != nil && *updateRequest.Name != "" && *updateRequest.Name != currentBeerStyle.Name {
-		if err := bc.ValidationService.ValidateUniqueNameForUpdate(*updateRequest.Name, currentBeerStyle.UUID); err != nil {
-			log.Printf("controller=BeerController func=UpdateBeerStyle beerUUID=%s err=%v", beerUUID, err)
-
-			if strings.Contains(err.Error(), "already exists") {
-				c.JSON(http.StatusConflict, gin.H{
-					"message": err.Error(),
-				})
-				return
-			}
-
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"message": "failed to validate beer style name",
-			})
-			return
-		}
+	if !ifMatchSatisfied(ifMatch, beerStyleETag(currentBeerStyle)) {
+		respondPreconditionFailed(c, currentBeerStyle)
+		return
 	}
 
 	changed := bc.UpdateService.ApplyBeerStyleUpdates(&currentBeerStyle, updateRequest)
@@ -200,6 +277,7 @@ func (bc *BeerController) UpdateBeerStyle(c *gin.Context) {
 	}
 
 	if !changed {
+		c.Header("ETag", beerStyleETag(currentBeerStyle))
 		c.JSON(http.StatusOK, gin.H{
 			"message": "No changes detected.",
 			"data":    currentBeerStyle,
@@ -207,21 +285,126 @@ func (bc *BeerController) UpdateBeerStyle(c *gin.Context) {
 		return
 	}
 
-	updatedBeerStyle, err := bc.BeerService.UpdateBeerStyle(currentBeerStyle)
+	bc.saveBeerStyle(c, "UpdateBeerStyle", currentBeerStyle)
+}
+
+// PatchBeerStyle applies a JSON Merge Patch (RFC 7396) or JSON Patch
+// (RFC 6902) document, selected by Content-Type, to a beer style.
+func (bc *BeerController) PatchBeerStyle(c *gin.Context) {
+	beerUUID := c.Param("beerUUID")
+	if err := bc.ValidationService.ValidateUUID(beerUUID); err != nil {
+		log.Printf("controller=BeerController func=PatchBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ifMatch := c.GetHeader("If-Match")
+	if strings.TrimSpace(ifMatch) == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{
+			"message": "If-Match header is required",
+		})
+		return
+	}
+
+	contentType := c.ContentType()
+	if contentType != mergePatchContentType && contentType != jsonPatchContentType {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"message": fmt.Sprintf("Content-Type must be %s or %s", mergePatchContentType, jsonPatchContentType),
+		})
+		return
+	}
+
+	patch, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		log.Printf("controller=BeerController func=UpdateBeerStyle beerUUID=%s err=%v", beerUUID, err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"message": "failed to update beer style",
+		log.Printf("controller=BeerController func=PatchBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "invalid request body",
+		})
+		return
+	}
+
+	currentBeerStyle, err := bc.BeerService.GetBeerStyleByUUID(c.Request.Context(), beerUUID)
+	if err != nil {
+		log.Printf("controller=BeerController func=PatchBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		respondError(c, err, "internal error")
+		return
+	}
+
+	if !ifMatchSatisfied(ifMatch, beerStyleETag(currentBeerStyle)) {
+		respondPreconditionFailed(c, currentBeerStyle)
+		return
+	}
+
+	var patchedBeerStyle domain.BeerStyle
+	if contentType == mergePatchContentType {
+		patchedBeerStyle, err = bc.UpdateService.ApplyMergePatch(currentBeerStyle, patch)
+	} else {
+		patchedBeerStyle, err = bc.UpdateService.ApplyJSONPatch(currentBeerStyle, patch)
+	}
+	if err != nil {
+		log.Printf("controller=BeerController func=PatchBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		respondError(c, err, "failed to apply patch")
+		return
+	}
+
+	if err := bc.ValidationService.ValidateTemperatureRange(patchedBeerStyle); err != nil {
+		log.Printf("controller=BeerController func=PatchBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if patchedBeerStyle.Name == currentBeerStyle.Name &&
+		patchedBeerStyle.TempMin == currentBeerStyle.TempMin &&
+		patchedBeerStyle.TempMax == currentBeerStyle.TempMax {
+		c.Header("ETag", beerStyleETag(currentBeerStyle))
+		c.JSON(http.StatusOK, gin.H{
+			"message": "No changes detected.",
+			"data":    currentBeerStyle,
 		})
 		return
 	}
 
+	bc.saveBeerStyle(c, "PatchBeerStyle", patchedBeerStyle)
+}
+
+// saveBeerStyle persists an edited beer style and answers with its new ETag.
+// A concurrent write caught by the repository's version check becomes a 412
+// carrying the latest representation.
+func (bc *BeerController) saveBeerStyle(c *gin.Context, funcName string, beerStyle domain.BeerStyle) {
+	updatedBeerStyle, err := bc.BeerService.UpdateBeerStyle(c.Request.Context(), beerStyle)
+	if err != nil {
+		log.Printf("controller=BeerController func=%s beerUUID=%s err=%v", funcName, beerStyle.UUID, err)
+		if errors.Is(err, domain.ErrPreconditionFailed) {
+			if latest, getErr := bc.BeerService.GetBeerStyleByUUID(c.Request.Context(), beerStyle.UUID); getErr == nil {
+				respondPreconditionFailed(c, latest)
+				return
+			}
+		}
+		respondError(c, err, "failed to update beer style")
+		return
+	}
+
+	c.Header("ETag", beerStyleETag(updatedBeerStyle))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Beer style updated.",
 		"data":    updatedBeerStyle,
 	})
 }
 
+// respondPreconditionFailed answers a stale If-Match with the current
+// representation and its ETag, so the client can merge and retry.
+func respondPreconditionFailed(c *gin.Context, current domain.BeerStyle) {
+	c.Header("ETag", beerStyleETag(current))
+	c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{
+		"message": "beer style was modified by another request",
+		"data":    current,
+	})
+}
+
 func (bc *BeerController) DeleteBeerStyle(c *gin.Context) {
 	beerUUID := c.Param("beerUUID")
 	if beerUUID == "" {
@@ -231,36 +414,121 @@ func (bc *BeerController) DeleteBeerStyle(c *gin.Context) {
 		return
 	}
 
-	_, err := bc.BeerService.GetBeerStyleByUUID(beerUUID)
+	_, err := bc.BeerService.GetBeerStyleByUUID(c.Request.Context(), beerUUID)
 	if err != nil {
 		log.Printf("controller=BeerController func=DeleteBeerStyle beerUUID=%s err=%v", beerUUID, err)
-		status := http.StatusInternalServerError
-		message := "internal error"
+		respondError(c, err, "internal error")
+		return
+	}
 
-		if bc.ValidationService.IsNoRowsError(err) {
-			status = http.StatusNotFound
-			message = "beer style not found"
-		}
+	err = bc.BeerService.DeleteBeerStyle(c.Request.Context(), beerUUID)
+	if err != nil {
+		log.Printf("controller=BeerController func=DeleteBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		respondError(c, err, "internal error")
+		return
+	}
 
-		c.AbortWithStatusJSON(status, gin.H{
-			"message": message,
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Beer style deleted successfully",
+	})
+}
+
+func (bc *BeerController) RestoreBeerStyle(c *gin.Context) {
+	beerUUID := c.Param("beerUUID")
+	if err := bc.ValidationService.ValidateUUID(beerUUID); err != nil {
+		log.Printf("controller=BeerController func=RestoreBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
 		})
 		return
 	}
 
-	err = bc.BeerService.DeleteBeerStyle(beerUUID)
+	beerStyle, err := bc.BeerService.GetBeerStyleByUUIDIncludingDeleted(c.Request.Context(), beerUUID)
 	if err != nil {
-		log.Printf("controller=BeerController func=DeleteBeerStyle beerUUID=%s err=%v", beerUUID, err)
-		status := http.StatusInternalServerError
-		message := "internal error"
+		log.Printf("controller=BeerController func=RestoreBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		respondError(c, err, "internal error")
+		return
+	}
 
-		c.AbortWithStatusJSON(status, gin.H{
-			"message": message,
+	if beerStyle.DeletedAt == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"message": "beer style is not deleted",
 		})
 		return
 	}
 
+	restoredBeerStyle, err := bc.BeerService.RestoreBeerStyle(c.Request.Context(), beerUUID)
+	if err != nil {
+		log.Printf("controller=BeerController func=RestoreBeerStyle beerUUID=%s err=%v", beerUUID, err)
+		respondError(c, err, "failed to restore beer style")
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Beer style deleted successfully",
+		"message": "Beer style restored.",
+		"data":    restoredBeerStyle,
 	})
 }
+
+func (bc *BeerController) GetBeerStyleHistory(c *gin.Context) {
+	beerUUID := c.Param("beerUUID")
+	if err := bc.ValidationService.ValidateUUID(beerUUID); err != nil {
+		log.Printf("controller=BeerController func=GetBeerStyleHistory beerUUID=%s err=%v", beerUUID, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	params, err := parseBeerStyleHistoryParams(c, beerUUID)
+	if err != nil {
+		log.Printf("controller=BeerController func=GetBeerStyleHistory beerUUID=%s err=%v", beerUUID, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if _, err := bc.BeerService.GetBeerStyleByUUIDIncludingDeleted(c.Request.Context(), beerUUID); err != nil {
+		log.Printf("controller=BeerController func=GetBeerStyleHistory beerUUID=%s err=%v", beerUUID, err)
+		respondError(c, err, "internal error")
+		return
+	}
+
+	page, err := bc.BeerService.ListBeerStyleHistory(c.Request.Context(), params)
+	if err != nil {
+		log.Printf("controller=BeerController func=GetBeerStyleHistory beerUUID=%s err=%v", beerUUID, err)
+		respondError(c, err, "internal error")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func parseBeerStyleHistoryParams(c *gin.Context, beerUUID string) (domain.BeerStyleHistoryParams, error) {
+	params := domain.BeerStyleHistoryParams{
+		BeerStyleUUID: beerUUID,
+		Limit:         service.DefaultBeerStyleListLimit,
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return params, domain.NewValidationError("limit must be an integer")
+		}
+		if limit < 1 || limit > service.MaxBeerStyleListLimit {
+			return params, domain.NewValidationError("limit must be between 1 and %d", service.MaxBeerStyleListLimit)
+		}
+		params.Limit = limit
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		beforeID, err := service.DecodeHistoryCursor(raw)
+		if err != nil {
+			return params, err
+		}
+		params.BeforeID = beforeID
+	}
+
+	return params, nil
+}
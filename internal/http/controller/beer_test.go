@@ -2,10 +2,13 @@ package controller
 
 import (
 	"backend-test/internal/domain"
+	"backend-test/internal/service"
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,18 +17,44 @@ import (
 
 type mockBeerService struct {
 	beers       []domain.BeerStyle
+	playlists   []domain.BeerStylePlaylist
 	shouldError bool
 	errorMsg    string
+	updateErr   error
+	listCalls   int
 }
 
-func (m *mockBeerService) ListAllBeerStyles() ([]domain.BeerStyle, error) {
+func (m *mockBeerService) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
+	m.listCalls++
 	if m.shouldError {
 		return nil, &testError{message: m.errorMsg}
 	}
 	return m.beers, nil
 }
 
-func (m *mockBeerService) GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle, error) {
+func (m *mockBeerService) ListBeerStyles(ctx context.Context, params domain.BeerStyleListParams) (domain.BeerStylePage, error) {
+	if m.shouldError {
+		return domain.BeerStylePage{}, &testError{message: m.errorMsg}
+	}
+	return domain.BeerStylePage{
+		BeerStyles: m.beers,
+		Paging:     domain.Paging{Limit: params.Limit, Sort: params.SortBy, Order: params.SortDir},
+	}, nil
+}
+
+func (m *mockBeerService) GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	if m.shouldError {
+		return domain.BeerStyle{}, &testError{message: m.errorMsg}
+	}
+	for _, beer := range m.beers {
+		if beer.UUID == beerUUID && beer.DeletedAt == nil {
+			return beer, nil
+		}
+	}
+	return domain.BeerStyle{}, domain.NewNotFoundError("beer style not found")
+}
+
+func (m *mockBeerService) GetBeerStyleByUUIDIncludingDeleted(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
 	if m.shouldError {
 		return domain.BeerStyle{}, &testError{message: m.errorMsg}
 	}
@@ -34,10 +63,10 @@ func (m *mockBeerService) GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle,
 			return beer, nil
 		}
 	}
-	return domain.BeerStyle{}, &testError{message: "not found"}
+	return domain.BeerStyle{}, domain.NewNotFoundError("beer style not found")
 }
 
-func (m *mockBeerService) CreateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+func (m *mockBeerService) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
 	if m.shouldError {
 		return domain.BeerStyle{}, &testError{message: m.errorMsg}
 	}
@@ -47,20 +76,164 @@ func (m *mockBeerService) CreateBeerStyle(beerStyle domain.BeerStyle) (domain.Be
 	return beerStyle, nil
 }
 
-func (m *mockBeerService) UpdateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+func (m *mockBeerService) UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
 	if m.shouldError {
 		return domain.BeerStyle{}, &testError{message: m.errorMsg}
 	}
+	if m.updateErr != nil {
+		return domain.BeerStyle{}, m.updateErr
+	}
+	beerStyle.Version++
 	return beerStyle, nil
 }
 
-func (m *mockBeerService) DeleteBeerStyle(beerUUID string) error {
+func (m *mockBeerService) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
 	if m.shouldError {
 		return &testError{message: m.errorMsg}
 	}
 	return nil
 }
 
+func (m *mockBeerService) ListBeerStyleHistory(ctx context.Context, params domain.BeerStyleHistoryParams) (domain.BeerStyleHistoryPage, error) {
+	if m.shouldError {
+		return domain.BeerStyleHistoryPage{}, &testError{message: m.errorMsg}
+	}
+	return domain.BeerStyleHistoryPage{
+		History: []domain.BeerStyleAuditEntry{},
+		Paging:  domain.Paging{Limit: params.Limit, Sort: "created_at", Order: "desc"},
+	}, nil
+}
+
+func (m *mockBeerService) RestoreBeerStyle(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	if m.shouldError {
+		return domain.BeerStyle{}, &testError{message: m.errorMsg}
+	}
+	for _, beer := range m.beers {
+		if beer.UUID == beerUUID {
+			beer.DeletedAt = nil
+			return beer, nil
+		}
+	}
+	return domain.BeerStyle{}, domain.NewNotFoundError("beer style not found")
+}
+
+func (m *mockBeerService) ExportBeerStyles(ctx context.Context, fn func([]domain.BeerStyle) error) error {
+	if m.shouldError {
+		return &testError{message: m.errorMsg}
+	}
+	if len(m.beers) == 0 {
+		return nil
+	}
+	return fn(m.beers)
+}
+
+func (m *mockBeerService) BeerStyleNameKeys(ctx context.Context, names []string) (map[string]string, error) {
+	if m.shouldError {
+		return nil, &testError{message: m.errorMsg}
+	}
+	keys := make(map[string]string, len(names))
+	for _, name := range names {
+		keys[name] = domain.FoldBeerStyleName(name)
+	}
+	return keys, nil
+}
+
+func (m *mockBeerService) ListBeerStylePlaylists(ctx context.Context, beerUUID string) ([]domain.BeerStylePlaylist, error) {
+	if _, err := m.GetBeerStyleByUUID(ctx, beerUUID); err != nil {
+		return nil, err
+	}
+	playlists := []domain.BeerStylePlaylist{}
+	for _, playlist := range m.playlists {
+		if playlist.BeerStyleUUID == beerUUID {
+			playlists = append(playlists, playlist)
+		}
+	}
+	return playlists, nil
+}
+
+func (m *mockBeerService) CuratedPlaylists(ctx context.Context, beerUUID string) ([]domain.BeerStylePlaylist, error) {
+	if m.shouldError {
+		return nil, &testError{message: m.errorMsg}
+	}
+	playlists := []domain.BeerStylePlaylist{}
+	for _, playlist := range m.playlists {
+		if playlist.BeerStyleUUID == beerUUID {
+			playlists = append(playlists, playlist)
+		}
+	}
+	return playlists, nil
+}
+
+func (m *mockBeerService) AddBeerStylePlaylist(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error) {
+	if _, err := m.GetBeerStyleByUUID(ctx, playlist.BeerStyleUUID); err != nil {
+		return domain.BeerStylePlaylist{}, err
+	}
+	for _, existing := range m.playlists {
+		if existing.BeerStyleUUID == playlist.BeerStyleUUID && existing.PlaylistID == playlist.PlaylistID {
+			return domain.BeerStylePlaylist{}, domain.NewConflictError("playlist '%s' is already attached to this beer style", playlist.PlaylistID)
+		}
+	}
+	m.playlists = append(m.playlists, playlist)
+	return playlist, nil
+}
+
+func (m *mockBeerService) UpdateBeerStylePlaylist(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error) {
+	if _, err := m.GetBeerStyleByUUID(ctx, playlist.BeerStyleUUID); err != nil {
+		return domain.BeerStylePlaylist{}, err
+	}
+	for i, existing := range m.playlists {
+		if existing.BeerStyleUUID == playlist.BeerStyleUUID && existing.PlaylistID == playlist.PlaylistID {
+			m.playlists[i].Priority = playlist.Priority
+			return m.playlists[i], nil
+		}
+	}
+	return domain.BeerStylePlaylist{}, domain.NewNotFoundError("playlist '%s' is not attached to this beer style", playlist.PlaylistID)
+}
+
+func (m *mockBeerService) RemoveBeerStylePlaylist(ctx context.Context, beerUUID, playlistID string) error {
+	if _, err := m.GetBeerStyleByUUID(ctx, beerUUID); err != nil {
+		return err
+	}
+	for i, existing := range m.playlists {
+		if existing.BeerStyleUUID == beerUUID && existing.PlaylistID == playlistID {
+			m.playlists = append(m.playlists[:i], m.playlists[i+1:]...)
+			return nil
+		}
+	}
+	return domain.NewNotFoundError("playlist '%s' is not attached to this beer style", playlistID)
+}
+
+func (m *mockBeerService) ApplyBeerStyleBulk(ctx context.Context, request domain.BeerStyleBulkRequest, rejected []error) (domain.BeerStyleBulkResult, error) {
+	if m.shouldError {
+		return domain.BeerStyleBulkResult{}, &testError{message: m.errorMsg}
+	}
+
+	result := domain.BeerStyleBulkResult{Mode: request.Mode, Committed: true}
+	for i, operation := range request.Operations {
+		item := domain.BeerStyleBulkItemResult{Index: i, Op: operation.Op, UUID: operation.UUID, Status: domain.BulkItemSucceeded}
+		if i < len(rejected) && rejected[i] != nil {
+			item.Status = domain.BulkItemFailed
+			item.Err = rejected[i]
+			if request.Mode != domain.BulkModePartial {
+				result.Committed = false
+			}
+		}
+		result.Results = append(result.Results, item)
+	}
+
+	for i := range result.Results {
+		switch {
+		case result.Results[i].Status == domain.BulkItemFailed:
+			result.Failed++
+		case !result.Committed:
+			result.Results[i].Status = domain.BulkItemSkipped
+		default:
+			result.Succeeded++
+		}
+	}
+	return result, nil
+}
+
 type mockValidationService struct {
 	shouldError bool
 	errorMsg    string
@@ -75,28 +248,41 @@ func (m *mockValidationService) ValidateTemperatureRange(beerStyle domain.BeerSt
 
 func (m *mockValidationService) ValidateTemperatureInput(temperature float64) error {
 	if m.shouldError {
-		return &testError{message: m.errorMsg}
+		return domain.NewValidationError("%s", m.errorMsg)
+	}
+	return nil
+}
+
+func (m *mockValidationService) ValidateWeatherQuery(query domain.WeatherQuery) error {
+	if m.shouldError {
+		return domain.NewValidationError("%s", m.errorMsg)
 	}
 	return nil
 }
 
-func (m *mockValidationService) ValidateUniqueNameForCreate(name string) error {
+func (m *mockValidationService) ValidateListParams(params domain.BeerStyleListParams) error {
 	if m.shouldError {
 		return &testError{message: m.errorMsg}
 	}
 	return nil
 }
 
-func (m *mockValidationService) ValidateUniqueNameForUpdate(name string, excludeUUID string) error {
+func (m *mockValidationService) ValidateUUID(uuidStr string) error {
+	if m.shouldError {
+		return &testError{message: m.errorMsg}
+	}
 	return nil
 }
 
-func (m *mockValidationService) IsNoRowsError(err error) bool {
-	return false
+func (m *mockValidationService) ValidateBulkOperations(ctx context.Context, operations []domain.BeerStyleBulkOperation) ([]error, error) {
+	if m.shouldError {
+		return nil, &testError{message: m.errorMsg}
+	}
+	return make([]error, len(operations)), nil
 }
 
-func (m *mockValidationService) ValidateUUID(uuidStr string) error {
-	return nil
+func (m *mockValidationService) ValidateBulkOperationsAgainst(beerStyles []domain.BeerStyle, keys map[string]string, operations []domain.BeerStyleBulkOperation) []error {
+	return make([]error, len(operations))
 }
 
 type mockUpdateService struct{}
@@ -105,6 +291,14 @@ func (m *mockUpdateService) ApplyBeerStyleUpdates(current *domain.BeerStyle, upd
 	return false
 }
 
+func (m *mockUpdateService) ApplyMergePatch(current domain.BeerStyle, patch []byte) (domain.BeerStyle, error) {
+	return service.NewUpdateService().ApplyMergePatch(current, patch)
+}
+
+func (m *mockUpdateService) ApplyJSONPatch(current domain.BeerStyle, patch []byte) (domain.BeerStyle, error) {
+	return service.NewUpdateService().ApplyJSONPatch(current, patch)
+}
+
 type testError struct {
 	message string
 }
@@ -157,6 +351,7 @@ func TestBeerController_ListAllBeerStyles_Success(t *testing.T) {
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
 
 	controller.ListAllBeerStyles(c)
 
@@ -189,6 +384,7 @@ func TestBeerController_ListAllBeerStyles_ServiceError(t *testing.T) {
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
 
 	controller.ListAllBeerStyles(c)
 
@@ -197,6 +393,135 @@ func TestBeerController_ListAllBeerStyles_ServiceError(t *testing.T) {
 	}
 }
 
+func TestBeerController_ListAllBeerStyles_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	controller := setupController()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/?limit=abc", nil)
+
+	controller.ListAllBeerStyles(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestBeerController_GetBeerStyle_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	beerService := &mockBeerService{
+		beers: []domain.BeerStyle{
+			{UUID: "test-uuid-1", Name: "Test IPA", TempMin: 4.0, TempMax: 7.0, UpdatedAt: time.Now()},
+		},
+	}
+	controller := NewBeerController(beerService, &mockValidationService{}, &mockUpdateService{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Params = []gin.Param{{Key: "beerUUID", Value: "test-uuid-1"}}
+
+	controller.GetBeerStyle(c)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if w.Header().Get("ETag") == "" {
+		t.Error("Expected ETag header to be set")
+	}
+
+	var response struct {
+		Data domain.BeerStyle `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Data.Name != "Test IPA" {
+		t.Errorf("Expected beer name 'Test IPA', got '%s'", response.Data.Name)
+	}
+}
+
+func TestBeerController_GetBeerStyle_NotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	beerStyle := domain.BeerStyle{UUID: "test-uuid-1", Name: "Test IPA", TempMin: 4.0, TempMax: 7.0, UpdatedAt: time.Now()}
+	beerService := &mockBeerService{beers: []domain.BeerStyle{beerStyle}}
+	controller := NewBeerController(beerService, &mockValidationService{}, &mockUpdateService{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Request.Header.Set("If-None-Match", beerStyleETag(beerStyle))
+	c.Params = []gin.Param{{Key: "beerUUID", Value: "test-uuid-1"}}
+
+	controller.GetBeerStyle(c)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected status %d, got %d", http.StatusNotModified, w.Code)
+	}
+
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected empty body, got '%s'", w.Body.String())
+	}
+}
+
+func TestBeerController_GetBeerStyle_InvalidUUID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	validationService := &mockValidationService{shouldError: true, errorMsg: "invalid UUID format: abc"}
+	controller := NewBeerController(&mockBeerService{}, validationService, &mockUpdateService{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Params = []gin.Param{{Key: "beerUUID", Value: "abc"}}
+
+	controller.GetBeerStyle(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestBeerController_GetBeerStyle_IncludeDeleted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	deletedAt := time.Now()
+	beerService := &mockBeerService{
+		beers: []domain.BeerStyle{
+			{UUID: "test-uuid-1", Name: "Test IPA", TempMin: 4.0, TempMax: 7.0, DeletedAt: &deletedAt},
+		},
+	}
+	controller := NewBeerController(beerService, &mockValidationService{}, &mockUpdateService{})
+
+	tests := []struct {
+		query          string
+		expectedStatus int
+	}{
+		{"", http.StatusNotFound},
+		{"?include_deleted=true", http.StatusOK},
+		{"?include_deleted=maybe", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/"+tt.query, nil)
+		c.Params = []gin.Param{{Key: "beerUUID", Value: "test-uuid-1"}}
+
+		controller.GetBeerStyle(c)
+
+		if w.Code != tt.expectedStatus {
+			t.Errorf("GET %q: expected status %d, got %d", tt.query, tt.expectedStatus, w.Code)
+		}
+	}
+}
+
 func TestBeerController_CreateBeerStyle_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -255,6 +580,7 @@ func TestBeerController_UpdateBeerStyle_Success(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest("PUT", "/", bytes.NewBuffer(jsonBody))
 	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("If-Match", beerStyleETag(beerService.beers[0]))
 	c.Params = []gin.Param{{Key: "beerUUID", Value: "test-uuid-1"}}
 
 	controller.UpdateBeerStyle(c)
@@ -264,6 +590,364 @@ func TestBeerController_UpdateBeerStyle_Success(t *testing.T) {
 	}
 }
 
+func TestBeerController_UpdateBeerStyle_InvalidUUID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	beerService := &mockBeerService{shouldError: true, errorMsg: "should not be reached"}
+	controller := NewBeerController(beerService, service.NewValidationService(beerService, service.NewUpdateService()), &mockUpdateService{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("PUT", "/", bytes.NewBufferString(`{"name": "Updated Beer"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("If-Match", "*")
+	c.Params = []gin.Param{{Key: "beerUUID", Value: "abc"}}
+
+	controller.UpdateBeerStyle(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var response map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response["message"] != "invalid UUID format: abc" {
+		t.Errorf("Expected message 'invalid UUID format: abc', got '%s'", response["message"])
+	}
+}
+
+func TestBeerController_UpdateBeerStyle_Preconditions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	current := domain.BeerStyle{UUID: "test-uuid-1", Name: "Test IPA", TempMin: 4.0, TempMax: 7.0, Version: 3}
+	stale := current
+	stale.Version = 2
+
+	tests := []struct {
+		name           string
+		ifMatch        string
+		updateErr      error
+		expectedStatus int
+	}{
+		{"missing If-Match", "", nil, http.StatusPreconditionRequired},
+		{"stale If-Match", beerStyleETag(stale), nil, http.StatusPreconditionFailed},
+		{"weak If-Match", "W/" + beerStyleETag(current), nil, http.StatusPreconditionFailed},
+		{"concurrent write", beerStyleETag(current), domain.NewPreconditionFailedError("beer style was modified by another request"), http.StatusPreconditionFailed},
+		{"wildcard If-Match", "*", nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beerService := &mockBeerService{beers: []domain.BeerStyle{current}, updateErr: tt.updateErr}
+			controller := NewBeerController(beerService, &mockValidationService{}, service.NewUpdateService())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("PUT", "/", bytes.NewBufferString(`{"name": "Updated Beer"}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+			c.Params = []gin.Param{{Key: "beerUUID", Value: "test-uuid-1"}}
+
+			controller.UpdateBeerStyle(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if w.Code != http.StatusPreconditionFailed {
+				return
+			}
+
+			var response struct {
+				Data domain.BeerStyle `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+
+			if response.Data.Version != current.Version || w.Header().Get("ETag") != beerStyleETag(current) {
+				t.Errorf("Expected the current representation and ETag, got %+v / %s", response.Data, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestBeerController_PatchBeerStyle_InvalidUUID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	beerService := &mockBeerService{}
+	controller := NewBeerController(beerService, service.NewValidationService(beerService, service.NewUpdateService()), &mockUpdateService{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("PATCH", "/", bytes.NewBufferString(`{"name": "Session IPA"}`))
+	c.Request.Header.Set("Content-Type", "application/merge-patch+json")
+	c.Request.Header.Set("If-Match", "*")
+	c.Params = []gin.Param{{Key: "beerUUID", Value: "abc"}}
+
+	controller.PatchBeerStyle(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var response map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response["message"] != "invalid UUID format: abc" {
+		t.Errorf("Expected message 'invalid UUID format: abc', got '%s'", response["message"])
+	}
+}
+
+func TestBeerController_PatchBeerStyle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	current := domain.BeerStyle{UUID: "0f8fad5b-d9cb-469f-a165-70867728950e", Name: "Test IPA", TempMin: 4.0, TempMax: 7.0, Version: 3}
+	other := domain.BeerStyle{UUID: "7c9e6679-7425-40de-944b-e07fc1f90ae7", Name: "Stout", TempMin: 8.0, TempMax: 12.0, Version: 1}
+
+	tests := []struct {
+		name           string
+		contentType    string
+		ifMatch        string
+		body           string
+		expectedStatus int
+		expectedName   string
+		updateErr      error
+	}{
+		{"merge patch", "application/merge-patch+json", beerStyleETag(current), `{"name": "Session IPA"}`, http.StatusOK, "Session IPA", nil},
+		{"json patch", "application/json-patch+json; charset=utf-8", beerStyleETag(current), `[{"op": "test", "path": "/name", "value": "Test IPA"}, {"op": "replace", "path": "/temp_max", "value": 6}]`, http.StatusOK, "Test IPA", nil},
+		{"no changes", "application/merge-patch+json", beerStyleETag(current), `{"name": "Test IPA"}`, http.StatusOK, "Test IPA", nil},
+		{"failed test op", "application/json-patch+json", beerStyleETag(current), `[{"op": "test", "path": "/name", "value": "Lager"}]`, http.StatusConflict, "", nil},
+		{"null required field", "application/merge-patch+json", beerStyleETag(current), `{"temp_min": null}`, http.StatusBadRequest, "", nil},
+		{"invalid range", "application/merge-patch+json", beerStyleETag(current), `{"temp_min": 9}`, http.StatusBadRequest, "", nil},
+		{"name taken", "application/merge-patch+json", beerStyleETag(current), `{"name": "stout"}`, http.StatusConflict, "", domain.NewConflictError("beer style with name 'stout' already exists")},
+		{"plain json", "application/json", beerStyleETag(current), `{"name": "Session IPA"}`, http.StatusUnsupportedMediaType, "", nil},
+		{"missing If-Match", "application/merge-patch+json", "", `{"name": "Session IPA"}`, http.StatusPreconditionRequired, "", nil},
+		{"stale If-Match", "application/merge-patch+json", `"stale"`, `{"name": "Session IPA"}`, http.StatusPreconditionFailed, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beerService := &mockBeerService{beers: []domain.BeerStyle{current, other}, updateErr: tt.updateErr}
+			controller := NewBeerController(beerService, service.NewValidationService(beerService, service.NewUpdateService()), &mockUpdateService{})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("PATCH", "/", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", tt.contentType)
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+			c.Params = []gin.Param{{Key: "beerUUID", Value: current.UUID}}
+
+			controller.PatchBeerStyle(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data domain.BeerStyle `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+
+			if response.Data.Name != tt.expectedName {
+				t.Errorf("Expected name %q, got %q", tt.expectedName, response.Data.Name)
+			}
+			if w.Header().Get("ETag") != beerStyleETag(response.Data) {
+				t.Errorf("Expected ETag %s, got %s", beerStyleETag(response.Data), w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestBeerController_BulkBeerStyles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	existing := domain.BeerStyle{UUID: "0f8fad5b-d9cb-469f-a165-70867728950e", Name: "Test IPA", TempMin: 4.0, TempMax: 7.0, Version: 1}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedCodes  []int
+	}{
+		{
+			"atomic success",
+			`{"operations": [{"op": "create", "name": "Lager", "temp_min": 2, "temp_max": 6}, {"op": "delete", "uuid": "0f8fad5b-d9cb-469f-a165-70867728950e"}]}`,
+			http.StatusOK,
+			[]int{http.StatusCreated, http.StatusNoContent},
+		},
+		{
+			"atomic duplicate names",
+			`{"operations": [{"op": "create", "name": "Lager", "temp_min": 2, "temp_max": 6}, {"op": "create", "name": "Lager", "temp_min": 2, "temp_max": 6}]}`,
+			http.StatusConflict,
+			[]int{0, http.StatusConflict},
+		},
+		{
+			"partial keeps valid items",
+			`{"mode": "partial", "operations": [{"op": "create", "name": "Test IPA", "temp_min": 2, "temp_max": 6}, {"op": "update", "uuid": "0f8fad5b-d9cb-469f-a165-70867728950e", "temp_max": 8}]}`,
+			http.StatusOK,
+			[]int{http.StatusConflict, http.StatusOK},
+		},
+		{"unknown mode", `{"mode": "eventual", "operations": [{"op": "delete"}]}`, http.StatusBadRequest, nil},
+		{"empty batch", `{"operations": []}`, http.StatusBadRequest, nil},
+		{"malformed body", `{"operations": `, http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beerService := &mockBeerService{beers: []domain.BeerStyle{existing}}
+			controller := NewBeerController(beerService, service.NewValidationService(beerService, service.NewUpdateService()), &mockUpdateService{})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			controller.BulkBeerStyles(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+
+			if tt.expectedCodes == nil {
+				return
+			}
+
+			var response struct {
+				Data domain.BeerStyleBulkResult `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+
+			if len(response.Data.Results) != len(tt.expectedCodes) {
+				t.Fatalf("Expected %d results, got %d", len(tt.expectedCodes), len(response.Data.Results))
+			}
+			for i, code := range tt.expectedCodes {
+				if response.Data.Results[i].Code != code {
+					t.Errorf("result %d: expected code %d, got %d", i, code, response.Data.Results[i].Code)
+				}
+			}
+		})
+	}
+}
+
+func TestBeerController_ExportBeerStyles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	beerService := &mockBeerService{beers: []domain.BeerStyle{
+		{UUID: "uuid-1", Name: "IPA", TempMin: 7, TempMax: 10, Version: 1},
+		{UUID: "uuid-2", Name: "Stout", TempMin: 8, TempMax: 12, Version: 2},
+	}}
+	controller := NewBeerController(beerService, &mockValidationService{}, &mockUpdateService{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/?format=csv", nil)
+
+	controller.ExportBeerStyles(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") || !strings.Contains(w.Header().Get("Content-Disposition"), "beer-styles.csv") {
+		t.Errorf("Unexpected headers %v", w.Header())
+	}
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "uuid,name,temp_min,temp_max") || !strings.HasPrefix(lines[2], "uuid-2,Stout,8,12,2,") {
+		t.Errorf("Unexpected CSV export %q", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/?format=xml", nil)
+
+	controller.ExportBeerStyles(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an unknown format, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestBeerController_ImportBeerStyles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	existing := domain.BeerStyle{UUID: "0f8fad5b-d9cb-469f-a165-70867728950e", Name: "IPA", TempMin: 7, TempMax: 10, Version: 1}
+
+	tests := []struct {
+		name            string
+		url             string
+		contentType     string
+		body            string
+		expectedStatus  int
+		expectedCommit  bool
+		expectedActions string
+	}{
+		{"dry run", "/?dry_run=true", "text/csv", "name,temp_min,temp_max\nIPA,7,11\nLager,2,6\nLager,2,6\n", http.StatusOK, false, "update,create,conflict"},
+		{"rejects the whole file", "/", "text/csv", "name,temp_min,temp_max\nLager,2,6\nPilsner,8,2\n", http.StatusBadRequest, false, "create,invalid"},
+		{"imports ndjson", "/?format=ndjson", "application/octet-stream", "{\"name\": \"IPA\", \"temp_min\": 7, \"temp_max\": 10}\n{\"name\": \"Lager\", \"temp_min\": 2, \"temp_max\": 6}\n", http.StatusOK, true, "unchanged,create"},
+		{"missing column", "/", "text/csv", "name,temp_min\nLager,2\n", http.StatusBadRequest, false, ""},
+		{"invalid dry_run", "/?dry_run=maybe", "text/csv", "", http.StatusBadRequest, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beerService := &mockBeerService{beers: []domain.BeerStyle{existing}}
+			controller := NewBeerController(beerService, service.NewValidationService(beerService, service.NewUpdateService()), &mockUpdateService{})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", tt.url, bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", tt.contentType)
+
+			controller.ImportBeerStyles(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+
+			if tt.expectedActions == "" {
+				return
+			}
+			if beerService.listCalls != 1 {
+				t.Errorf("Expected the catalog to be read once, got %d reads", beerService.listCalls)
+			}
+
+			var response struct {
+				Data domain.BeerStyleImportReport `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+
+			actions := make([]string, len(response.Data.Items))
+			for i, item := range response.Data.Items {
+				actions[i] = item.Action
+			}
+			if strings.Join(actions, ",") != tt.expectedActions {
+				t.Errorf("Expected actions %s, got %v", tt.expectedActions, actions)
+			}
+			if response.Data.Committed != tt.expectedCommit {
+				t.Errorf("Expected committed=%v, got %v", tt.expectedCommit, response.Data.Committed)
+			}
+		})
+	}
+}
+
 func TestBeerController_DeleteBeerStyle_Success(t *testing.T) {
 	beerService := &mockBeerService{
 		beers: []domain.BeerStyle{
@@ -285,6 +969,7 @@ func TestBeerController_DeleteBeerStyle_Success(t *testing.T) {
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("DELETE", "/", nil)
 	c.Params = []gin.Param{{Key: "beerUUID", Value: "test-uuid-1"}}
 
 	controller.DeleteBeerStyle(c)
@@ -316,6 +1001,7 @@ func TestBeerController_DeleteBeerStyle_ServiceError(t *testing.T) {
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("DELETE", "/", nil)
 	c.Params = []gin.Param{{Key: "beerUUID", Value: "test-uuid-1"}}
 
 	controller.DeleteBeerStyle(c)
@@ -324,3 +1010,133 @@ func TestBeerController_DeleteBeerStyle_ServiceError(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, w.Code)
 	}
 }
+
+func TestBeerController_RestoreBeerStyle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	deletedAt := time.Now()
+	beerService := &mockBeerService{
+		beers: []domain.BeerStyle{
+			{UUID: "deleted-uuid", Name: "Old IPA", TempMin: 4.0, TempMax: 7.0, DeletedAt: &deletedAt},
+			{UUID: "active-uuid", Name: "Lager", TempMin: 3.0, TempMax: 6.0},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		beerUUID       string
+		validation     *mockValidationService
+		expectedStatus int
+	}{
+		{"restores deleted style", "deleted-uuid", &mockValidationService{}, http.StatusOK},
+		{"rejects active style", "active-uuid", &mockValidationService{}, http.StatusConflict},
+		{"unknown style", "missing-uuid", &mockValidationService{}, http.StatusNotFound},
+		{"invalid uuid", "abc", &mockValidationService{shouldError: true, errorMsg: "invalid UUID format: abc"}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := NewBeerController(beerService, tt.validation, &mockUpdateService{})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/", nil)
+			c.Params = []gin.Param{{Key: "beerUUID", Value: tt.beerUUID}}
+
+			controller.RestoreBeerStyle(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestBeerController_GetBeerStyleHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	beerService := &mockBeerService{
+		beers: []domain.BeerStyle{{UUID: "test-uuid-1", Name: "Test IPA", TempMin: 4.0, TempMax: 7.0}},
+	}
+	controller := NewBeerController(beerService, &mockValidationService{}, &mockUpdateService{})
+
+	tests := []struct {
+		name           string
+		beerUUID       string
+		query          string
+		expectedStatus int
+	}{
+		{"lists history", "test-uuid-1", "?limit=10", http.StatusOK},
+		{"invalid limit", "test-uuid-1", "?limit=0", http.StatusBadRequest},
+		{"invalid cursor", "test-uuid-1", "?cursor=???", http.StatusBadRequest},
+		{"unknown style", "missing-uuid", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/"+tt.query, nil)
+			c.Params = []gin.Param{{Key: "beerUUID", Value: tt.beerUUID}}
+
+			controller.GetBeerStyleHistory(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestBeerController_BeerStylePlaylists(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	beerService := &mockBeerService{
+		beers: []domain.BeerStyle{{UUID: "test-uuid-1", Name: "Test IPA", TempMin: 4.0, TempMax: 7.0}},
+	}
+	controller := NewBeerController(beerService, &mockValidationService{}, &mockUpdateService{})
+
+	router := gin.New()
+	router.GET("/:beerUUID/playlists", controller.ListBeerStylePlaylists)
+	router.POST("/:beerUUID/playlists", controller.AddBeerStylePlaylist)
+	router.PUT("/:beerUUID/playlists/:playlistID", controller.UpdateBeerStylePlaylist)
+	router.DELETE("/:beerUUID/playlists/:playlistID", controller.RemoveBeerStylePlaylist)
+
+	steps := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{"attaches by id", "POST", "/test-uuid-1/playlists", `{"playlist_id": "37i9dQZF1DX0XUsuxWHRQd", "priority": 5}`, http.StatusCreated},
+		{"attaches by link", "POST", "/test-uuid-1/playlists", `{"playlist_id": "https://open.spotify.com/playlist/4bX0a1b2c3?si=abc"}`, http.StatusCreated},
+		{"rejects duplicate uri", "POST", "/test-uuid-1/playlists", `{"playlist_id": "spotify:playlist:37i9dQZF1DX0XUsuxWHRQd"}`, http.StatusConflict},
+		{"rejects other links", "POST", "/test-uuid-1/playlists", `{"playlist_id": "https://example.com/playlist/abc"}`, http.StatusBadRequest},
+		{"rejects empty id", "POST", "/test-uuid-1/playlists", `{"priority": 1}`, http.StatusBadRequest},
+		{"unknown style", "POST", "/missing-uuid/playlists", `{"playlist_id": "abc"}`, http.StatusNotFound},
+		{"updates priority", "PUT", "/test-uuid-1/playlists/4bX0a1b2c3", `{"priority": 10}`, http.StatusOK},
+		{"requires priority", "PUT", "/test-uuid-1/playlists/4bX0a1b2c3", `{}`, http.StatusBadRequest},
+		{"updates unknown playlist", "PUT", "/test-uuid-1/playlists/nope", `{"priority": 1}`, http.StatusNotFound},
+		{"removes playlist", "DELETE", "/test-uuid-1/playlists/37i9dQZF1DX0XUsuxWHRQd", "", http.StatusOK},
+		{"removes it again", "DELETE", "/test-uuid-1/playlists/37i9dQZF1DX0XUsuxWHRQd", "", http.StatusNotFound},
+		{"lists playlists", "GET", "/test-uuid-1/playlists", "", http.StatusOK},
+	}
+
+	for _, step := range steps {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(step.method, step.path, strings.NewReader(step.body))
+		router.ServeHTTP(w, req)
+
+		if w.Code != step.expectedStatus {
+			t.Fatalf("%s: Expected status %d, got %d: %s", step.name, step.expectedStatus, w.Code, w.Body.String())
+		}
+	}
+
+	if len(beerService.playlists) != 1 {
+		t.Fatalf("Expected 1 attached playlist, got %+v", beerService.playlists)
+	}
+	if playlist := beerService.playlists[0]; playlist.PlaylistID != "4bX0a1b2c3" || playlist.Priority != 10 {
+		t.Errorf("Expected playlist 4bX0a1b2c3 with priority 10, got %+v", playlist)
+	}
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/service"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulkBeerStyles creates, updates and deletes beer styles in one request.
+// The whole batch is validated up front against a single snapshot and then
+// applied in one transaction, either all-or-nothing ("atomic", the default)
+// or keeping whatever succeeds ("partial").
+func (bc *BeerController) BulkBeerStyles(c *gin.Context) {
+	var request domain.BeerStyleBulkRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("controller=BeerController func=BulkBeerStyles err=%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "invalid request body",
+		})
+		return
+	}
+
+	if request.Mode == "" {
+		request.Mode = domain.BulkModeAtomic
+	}
+	if request.Mode != domain.BulkModeAtomic && request.Mode != domain.BulkModePartial {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("mode must be '%s' or '%s'", domain.BulkModeAtomic, domain.BulkModePartial),
+		})
+		return
+	}
+
+	if len(request.Operations) == 0 || len(request.Operations) > service.MaxBulkOperations {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("operations must contain between 1 and %d items", service.MaxBulkOperations),
+		})
+		return
+	}
+
+	rejected, err := bc.ValidationService.ValidateBulkOperations(c.Request.Context(), request.Operations)
+	if err != nil {
+		log.Printf("controller=BeerController func=BulkBeerStyles err=%v", err)
+		respondError(c, err, "failed to validate bulk operations")
+		return
+	}
+
+	result, err := bc.BeerService.ApplyBeerStyleBulk(c.Request.Context(), request, rejected)
+	if err != nil {
+		log.Printf("controller=BeerController func=BulkBeerStyles err=%v", err)
+		respondError(c, err, "failed to apply bulk operations")
+		return
+	}
+
+	status := http.StatusOK
+	for i := range result.Results {
+		item := &result.Results[i]
+		item.Code = bulkItemStatus(*item)
+		if item.Err == nil {
+			continue
+		}
+
+		if item.Code == http.StatusInternalServerError {
+			log.Printf("controller=BeerController func=BulkBeerStyles index=%d err=%v", item.Index, item.Err)
+		}
+		item.Message = domain.ErrorMessage(item.Err, "internal error")
+		if !result.Committed && status == http.StatusOK {
+			status = item.Code
+		}
+	}
+
+	message := "Bulk operations applied."
+	if !result.Committed {
+		message = "No operations were applied."
+	}
+
+	c.JSON(status, gin.H{
+		"message": message,
+		"data":    result,
+	})
+}
+
+func bulkItemStatus(item domain.BeerStyleBulkItemResult) int {
+	if item.Err != nil {
+		return errorStatus(item.Err)
+	}
+	if item.Status != domain.BulkItemSucceeded {
+		return 0
+	}
+
+	switch item.Op {
+	case domain.BulkOperationCreate:
+		return http.StatusCreated
+	case domain.BulkOperationDelete:
+		return http.StatusNoContent
+	default:
+		return http.StatusOK
+	}
+}
@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/service"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportBytes bounds the size of an import upload.
+const maxImportBytes = 5 << 20
+
+// ExportBeerStyles streams the active catalog as CSV, JSON or NDJSON,
+// flushing after every chunk read from the database.
+func (bc *BeerController) ExportBeerStyles(c *gin.Context) {
+	format := c.DefaultQuery("format", domain.CatalogFormatJSON)
+
+	writer, err := service.NewBeerStyleExportWriter(format, c.Writer)
+	if err != nil {
+		respondError(c, err, "invalid export format")
+		return
+	}
+
+	// Headers are only sent once the first chunk arrives, so a failure
+	// before that can still be answered with a regular error.
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		c.Header("Content-Type", service.CatalogContentType(format))
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="beer-styles.%s"`, format))
+		c.Status(http.StatusOK)
+	}
+
+	err = bc.BeerService.ExportBeerStyles(c.Request.Context(), func(beerStyles []domain.BeerStyle) error {
+		start()
+		for _, beerStyle := range beerStyles {
+			if err := writer.Write(beerStyle); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err == nil {
+		start()
+		err = writer.Close()
+	}
+
+	if err != nil {
+		log.Printf("controller=BeerController func=ExportBeerStyles format=%s err=%v", format, err)
+		if !started {
+			respondError(c, err, "failed to export beer styles")
+			return
+		}
+		// The status line is gone already; cutting the stream short is the
+		// only way left to tell the client the export is incomplete.
+		c.Abort()
+		return
+	}
+
+	c.Writer.Flush()
+}
+
+// ImportBeerStyles creates or updates beer styles from a CSV, JSON or NDJSON
+// file, matching rows to the catalog by name. Every row is validated with the
+// same rules as the API; the file is applied only when all rows pass, in a
+// single transaction. With dry_run=true it only reports what would change.
+func (bc *BeerController) ImportBeerStyles(c *gin.Context) {
+	dryRun, err := parseBoolQuery(c, "dry_run")
+	if err != nil {
+		respondError(c, err, "invalid dry_run")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"message": fmt.Sprintf("import file must be at most %d bytes", maxImportBytes),
+			})
+			return
+		}
+		log.Printf("controller=BeerController func=ImportBeerStyles err=%v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "cannot read request body",
+		})
+		return
+	}
+
+	rows, err := service.DecodeBeerStyleImport(importFormat(c), bytes.NewReader(body))
+	if err != nil {
+		respondError(c, err, "invalid import file")
+		return
+	}
+
+	existing, err := bc.BeerService.ListAllBeerStyles(c.Request.Context())
+	if err != nil {
+		log.Printf("controller=BeerController func=ImportBeerStyles err=%v", err)
+		respondError(c, err, "failed to read beer styles")
+		return
+	}
+
+	keys, err := bc.BeerService.BeerStyleNameKeys(c.Request.Context(), service.ImportNameCandidates(rows, existing))
+	if err != nil {
+		log.Printf("controller=BeerController func=ImportBeerStyles err=%v", err)
+		respondError(c, err, "failed to read beer styles")
+		return
+	}
+
+	plan := service.PlanBeerStyleImport(rows, existing, keys)
+
+	rejected := bc.ValidationService.ValidateBulkOperationsAgainst(existing, keys, plan.Operations)
+	plan.Reject(rejected)
+
+	if dryRun {
+		respondImport(c, http.StatusOK, "Dry run: nothing was imported.", plan.Report(true, false))
+		return
+	}
+
+	if failed := plan.Failed(); failed != nil {
+		respondImport(c, errorStatus(failed.Err), "Import has rows that cannot be applied; nothing was imported.", plan.Report(false, false))
+		return
+	}
+
+	if len(plan.Operations) > 0 {
+		result, err := bc.BeerService.ApplyBeerStyleBulk(c.Request.Context(), domain.BeerStyleBulkRequest{
+			Mode:       domain.BulkModeAtomic,
+			Operations: plan.Operations,
+		}, rejected)
+		if err != nil {
+			log.Printf("controller=BeerController func=ImportBeerStyles err=%v", err)
+			respondError(c, err, "failed to import beer styles")
+			return
+		}
+
+		plan.Apply(result)
+		if !result.Committed {
+			failed := plan.Failed()
+			respondImport(c, errorStatus(failed.Err), "Import failed; nothing was imported.", plan.Report(false, false))
+			return
+		}
+	}
+
+	respondImport(c, http.StatusOK, "Beer styles imported.", plan.Report(false, true))
+}
+
+// importFormat takes the format query parameter, falling back to the
+// request's Content-Type.
+func importFormat(c *gin.Context) string {
+	if format := c.Query("format"); format != "" {
+		return format
+	}
+
+	switch c.ContentType() {
+	case "text/csv":
+		return domain.CatalogFormatCSV
+	case "application/x-ndjson":
+		return domain.CatalogFormatNDJSON
+	default:
+		return domain.CatalogFormatJSON
+	}
+}
+
+func respondImport(c *gin.Context, status int, message string, report domain.BeerStyleImportReport) {
+	for i := range report.Items {
+		item := &report.Items[i]
+		if item.Err == nil {
+			continue
+		}
+		if errorStatus(item.Err) == http.StatusInternalServerError {
+			log.Printf("controller=BeerController func=ImportBeerStyles row=%d err=%v", item.Row, item.Err)
+		}
+		item.Message = domain.ErrorMessage(item.Err, "internal error")
+	}
+
+	c.JSON(status, gin.H{
+		"message": message,
+		"data":    report,
+	})
+}
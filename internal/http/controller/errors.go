@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, domain.ErrUpstreamUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// respondError writes err using its domain classification. Unclassified
+// errors become a 500 with fallbackMessage so internal details never leak.
+func respondError(c *gin.Context, err error, fallbackMessage string) {
+	status := errorStatus(err)
+
+	message := fallbackMessage
+	if status != http.StatusInternalServerError {
+		message = domain.ErrorMessage(err, fallbackMessage)
+	}
+
+	c.AbortWithStatusJSON(status, gin.H{
+		"message": message,
+	})
+}
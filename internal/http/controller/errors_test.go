@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRespondError_MapsDomainErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name            string
+		err             error
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{"validation", domain.NewValidationError("name is required"), http.StatusBadRequest, "name is required"},
+		{"not found", domain.NewNotFoundError("beer style not found"), http.StatusNotFound, "beer style not found"},
+		{"conflict", domain.NewConflictError("beer style with name 'IPA' already exists"), http.StatusConflict, "beer style with name 'IPA' already exists"},
+		{"precondition failed", domain.NewPreconditionFailedError("beer style was modified by another request"), http.StatusPreconditionFailed, "beer style was modified by another request"},
+		{"upstream", domain.NewUpstreamUnavailableError(errors.New("dial tcp: timeout"), "music service is temporarily unavailable"), http.StatusServiceUnavailable, "music service is temporarily unavailable"},
+		{"wrapped", fmt.Errorf("failed to load: %w", domain.NewNotFoundError("beer style not found")), http.StatusNotFound, "beer style not found"},
+		{"unclassified", errors.New("pq: connection refused"), http.StatusInternalServerError, "internal error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			respondError(c, tt.err, "internal error")
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response map[string]string
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+
+			if response["message"] != tt.expectedMessage {
+				t.Errorf("Expected message '%s', got '%s'", tt.expectedMessage, response["message"])
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// beerStyleETag derives a strong validator from the row version, which the
+// repository bumps on every write.
+func beerStyleETag(beerStyle domain.BeerStyle) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%d", beerStyle.UUID, beerStyle.Version)))
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header value contains the
+// given entity tag. Weak validators are compared by their opaque value, as
+// RFC 9110 allows for If-None-Match.
+func etagMatches(header string, etag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ifMatchSatisfied evaluates an If-Match header against the current entity
+// tag. Unlike If-None-Match it uses strong comparison, so weak validators
+// never match.
+func ifMatchSatisfied(header string, etag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimSpace(candidate) == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (bc *BeerController) ListBeerStylePlaylists(c *gin.Context) {
+	beerUUID := c.Param("beerUUID")
+	if err := bc.ValidationService.ValidateUUID(beerUUID); err != nil {
+		log.Printf("controller=BeerController func=ListBeerStylePlaylists beerUUID=%s err=%v", beerUUID, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	playlists, err := bc.BeerService.ListBeerStylePlaylists(c.Request.Context(), beerUUID)
+	if err != nil {
+		log.Printf("controller=BeerController func=ListBeerStylePlaylists beerUUID=%s err=%v", beerUUID, err)
+		respondError(c, err, "internal error")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": playlists,
+	})
+}
+
+func (bc *BeerController) AddBeerStylePlaylist(c *gin.Context) {
+	beerUUID := c.Param("beerUUID")
+	if err := bc.ValidationService.ValidateUUID(beerUUID); err != nil {
+		log.Printf("controller=BeerController func=AddBeerStylePlaylist beerUUID=%s err=%v", beerUUID, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var request domain.BeerStylePlaylistRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("controller=BeerController func=AddBeerStylePlaylist beerUUID=%s err=%v", beerUUID, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "invalid request body",
+		})
+		return
+	}
+
+	playlistID, err := domain.ParsePlaylistID(request.PlaylistID)
+	if err != nil {
+		respondError(c, err, "invalid playlist_id")
+		return
+	}
+
+	playlist := domain.BeerStylePlaylist{BeerStyleUUID: beerUUID, PlaylistID: playlistID}
+	if request.Priority != nil {
+		playlist.Priority = *request.Priority
+	}
+
+	added, err := bc.BeerService.AddBeerStylePlaylist(c.Request.Context(), playlist)
+	if err != nil {
+		log.Printf("controller=BeerController func=AddBeerStylePlaylist beerUUID=%s playlistID=%s err=%v", beerUUID, playlistID, err)
+		respondError(c, err, "failed to attach playlist")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Playlist attached to beer style.",
+		"data":    added,
+	})
+}
+
+func (bc *BeerController) UpdateBeerStylePlaylist(c *gin.Context) {
+	beerUUID, playlistID, ok := bc.beerStylePlaylistParams(c, "UpdateBeerStylePlaylist")
+	if !ok {
+		return
+	}
+
+	var request domain.BeerStylePlaylistRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("controller=BeerController func=UpdateBeerStylePlaylist beerUUID=%s err=%v", beerUUID, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "invalid request body",
+		})
+		return
+	}
+
+	if request.Priority == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "priority is required",
+		})
+		return
+	}
+
+	updated, err := bc.BeerService.UpdateBeerStylePlaylist(c.Request.Context(), domain.BeerStylePlaylist{
+		BeerStyleUUID: beerUUID,
+		PlaylistID:    playlistID,
+		Priority:      *request.Priority,
+	})
+	if err != nil {
+		log.Printf("controller=BeerController func=UpdateBeerStylePlaylist beerUUID=%s playlistID=%s err=%v", beerUUID, playlistID, err)
+		respondError(c, err, "failed to update playlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Playlist priority updated.",
+		"data":    updated,
+	})
+}
+
+func (bc *BeerController) RemoveBeerStylePlaylist(c *gin.Context) {
+	beerUUID, playlistID, ok := bc.beerStylePlaylistParams(c, "RemoveBeerStylePlaylist")
+	if !ok {
+		return
+	}
+
+	if err := bc.BeerService.RemoveBeerStylePlaylist(c.Request.Context(), beerUUID, playlistID); err != nil {
+		log.Printf("controller=BeerController func=RemoveBeerStylePlaylist beerUUID=%s playlistID=%s err=%v", beerUUID, playlistID, err)
+		respondError(c, err, "failed to detach playlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Playlist detached from beer style.",
+	})
+}
+
+// beerStylePlaylistParams validates the path of a single attached playlist,
+// writing the error response itself when they are invalid.
+func (bc *BeerController) beerStylePlaylistParams(c *gin.Context, funcName string) (string, string, bool) {
+	beerUUID := c.Param("beerUUID")
+	if err := bc.ValidationService.ValidateUUID(beerUUID); err != nil {
+		log.Printf("controller=BeerController func=%s beerUUID=%s err=%v", funcName, beerUUID, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return "", "", false
+	}
+
+	playlistID, err := domain.ParsePlaylistID(c.Param("playlistID"))
+	if err != nil {
+		respondError(c, err, "invalid playlist_id")
+		return "", "", false
+	}
+
+	return beerUUID, playlistID, true
+}
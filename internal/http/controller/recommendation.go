@@ -3,8 +3,10 @@ package controller
 import (
 	"backend-test/internal/domain"
 	"backend-test/internal/service"
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -32,42 +34,111 @@ func (rc *RecommendationController) SuggestSpotifyPlaylist(c *gin.Context) {
 		return
 	}
 
-	if err := rc.ValidationService.ValidateTemperatureInput(request.Temperature); err != nil {
-		log.Printf("controller=RecommendationController func=SuggestSpotifyPlaylist temperature=%.1f err=%v", request.Temperature, err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": err.Error(),
-		})
+	options, err := parseRecommendationOptions(c, request)
+	if err != nil {
+		log.Printf("controller=RecommendationController func=SuggestSpotifyPlaylist err=%v", err)
+		respondError(c, err, "invalid recommendation options")
 		return
 	}
 
-	recommendation, err := rc.RecommendationService.GetRecommendationForTemperature(request.Temperature)
+	var recommendation *domain.RecommendationResponse
+	if request.Temperature != nil || !request.HasLocation() {
+		recommendation, err = rc.suggestForTemperature(c, request, options)
+	} else {
+		recommendation, err = rc.suggestForLocation(c, request, options)
+	}
 	if err != nil {
-		log.Printf("controller=RecommendationController func=SuggestSpotifyPlaylist temperature=%.1f err=%v", request.Temperature, err)
-
-		var status int
-		var message string
-
-		errorMsg := err.Error()
-		switch {
-		case strings.Contains(errorMsg, "no playlist found"):
-			status = http.StatusNotFound
-			message = errorMsg
-		case strings.Contains(errorMsg, "spotify service unavailable"):
-			status = http.StatusServiceUnavailable
-			message = "Spotify service is temporarily unavailable"
-		case strings.Contains(errorMsg, "failed to find best beer style"):
-			status = http.StatusInternalServerError
-			message = "Unable to determine suitable beer style"
-		default:
-			status = http.StatusInternalServerError
-			message = "Internal server error"
+		fallbackMessage := "Internal server error"
+		if errors.Is(err, service.ErrBeerStyleSelection) {
+			fallbackMessage = "Unable to determine suitable beer style"
 		}
 
-		c.JSON(status, gin.H{
-			"message": message,
-		})
+		respondError(c, err, fallbackMessage)
 		return
 	}
 
 	c.JSON(http.StatusOK, recommendation)
 }
+
+func (rc *RecommendationController) suggestForTemperature(c *gin.Context, request domain.TemperatureRequest, options domain.RecommendationOptions) (*domain.RecommendationResponse, error) {
+	// A body with neither temperature nor location still means 0°C, as it did
+	// before locations were accepted.
+	var temperature float64
+	if request.Temperature != nil {
+		temperature = *request.Temperature
+	}
+	if request.HasLocation() {
+		return nil, domain.NewValidationError("send either temperature or a location, not both")
+	}
+
+	if err := rc.ValidationService.ValidateTemperatureInput(temperature); err != nil {
+		log.Printf("controller=RecommendationController func=SuggestSpotifyPlaylist temperature=%.1f err=%v", temperature, err)
+		return nil, err
+	}
+
+	recommendation, err := rc.RecommendationService.GetRecommendationForTemperature(c.Request.Context(), temperature, options)
+	if err != nil {
+		log.Printf("controller=RecommendationController func=SuggestSpotifyPlaylist temperature=%.1f err=%v", temperature, err)
+	}
+	return recommendation, err
+}
+
+func (rc *RecommendationController) suggestForLocation(c *gin.Context, request domain.TemperatureRequest, options domain.RecommendationOptions) (*domain.RecommendationResponse, error) {
+	query := request.WeatherQuery()
+	if err := rc.ValidationService.ValidateWeatherQuery(query); err != nil {
+		log.Printf("controller=RecommendationController func=SuggestSpotifyPlaylist city=%q err=%v", query.City, err)
+		return nil, err
+	}
+
+	recommendation, err := rc.RecommendationService.GetRecommendationForLocation(c.Request.Context(), query, options)
+	if err != nil {
+		log.Printf("controller=RecommendationController func=SuggestSpotifyPlaylist city=%q err=%v", query.City, err)
+	}
+	return recommendation, err
+}
+
+// parseRecommendationOptions reads the query parameters that shape the
+// response: ?limit lists ranked beer styles, ?tracks, ?sampling and ?seed
+// pick the playlist tracks.
+func parseRecommendationOptions(c *gin.Context, request domain.TemperatureRequest) (domain.RecommendationOptions, error) {
+	options := domain.RecommendationOptions{
+		Strategy: request.Strategy,
+		Sampling: strings.ToLower(c.Query("sampling")),
+	}
+
+	var err error
+	if options.Limit, err = parseRangeQuery(c, "limit", service.MaxRecommendationCandidates); err != nil {
+		return options, err
+	}
+	if options.Tracks, err = parseRangeQuery(c, "tracks", service.MaxPlaylistTracks); err != nil {
+		return options, err
+	}
+
+	if raw := c.Query("seed"); raw != "" {
+		seed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return options, domain.NewValidationError("seed must be an integer")
+		}
+		options.Seed = &seed
+	}
+
+	return options, nil
+}
+
+// parseRangeQuery reads an optional integer between 1 and max, returning 0
+// when it is absent.
+func parseRangeQuery(c *gin.Context, name string, max int) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, domain.NewValidationError("%s must be an integer", name)
+	}
+	if value < 1 || value > max {
+		return 0, domain.NewValidationError("%s must be between 1 and %d", name, max)
+	}
+	return value, nil
+}
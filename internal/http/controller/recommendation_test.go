@@ -2,10 +2,14 @@ package controller
 
 import (
 	"backend-test/internal/domain"
+	"backend-test/internal/service"
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -14,10 +18,31 @@ import (
 type mockRecommendationService struct {
 	shouldError bool
 	errorMsg    string
+	err         error
 	response    domain.RecommendationResponse
+	options     domain.RecommendationOptions
+	temperature *float64
+	query       *domain.WeatherQuery
 }
 
-func (m *mockRecommendationService) GetRecommendationForTemperature(temperature float64) (*domain.RecommendationResponse, error) {
+func (m *mockRecommendationService) GetRecommendationForTemperature(ctx context.Context, temperature float64, options domain.RecommendationOptions) (*domain.RecommendationResponse, error) {
+	m.options = options
+	m.temperature = &temperature
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.shouldError {
+		return nil, &testError{message: m.errorMsg}
+	}
+	return &m.response, nil
+}
+
+func (m *mockRecommendationService) GetRecommendationForLocation(ctx context.Context, query domain.WeatherQuery, options domain.RecommendationOptions) (*domain.RecommendationResponse, error) {
+	m.options = options
+	m.query = &query
+	if m.err != nil {
+		return nil, m.err
+	}
 	if m.shouldError {
 		return nil, &testError{message: m.errorMsg}
 	}
@@ -66,7 +91,7 @@ func TestRecommendationController_SuggestSpotifyPlaylist_Success(t *testing.T) {
 	controller := setupRecommendationTestController()
 
 	requestBody := domain.TemperatureRequest{
-		Temperature: 6.0,
+		Temperature: ptrFloat64(6.0),
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -136,7 +161,7 @@ func TestRecommendationController_SuggestSpotifyPlaylist_ValidationError(t *test
 	controller := NewRecommendationController(recommendationService, validationService)
 
 	requestBody := domain.TemperatureRequest{
-		Temperature: 100.0, // Invalid temperature
+		Temperature: ptrFloat64(100.0), // Invalid temperature
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -167,15 +192,14 @@ func TestRecommendationController_SuggestSpotifyPlaylist_NoPlaylistFound(t *test
 	gin.SetMode(gin.TestMode)
 
 	recommendationService := &mockRecommendationService{
-		shouldError: true,
-		errorMsg:    "no playlist found for temperature",
+		err: domain.NewNotFoundError("no playlist found for temperature"),
 	}
 	validationService := &mockValidationService{}
 
 	controller := NewRecommendationController(recommendationService, validationService)
 
 	requestBody := domain.TemperatureRequest{
-		Temperature: 6.0,
+		Temperature: ptrFloat64(6.0),
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -206,15 +230,14 @@ func TestRecommendationController_SuggestSpotifyPlaylist_SpotifyUnavailable(t *t
 	gin.SetMode(gin.TestMode)
 
 	recommendationService := &mockRecommendationService{
-		shouldError: true,
-		errorMsg:    "spotify service unavailable",
+		err: domain.NewUpstreamUnavailableError(nil, "music service is temporarily unavailable"),
 	}
 	validationService := &mockValidationService{}
 
 	controller := NewRecommendationController(recommendationService, validationService)
 
 	requestBody := domain.TemperatureRequest{
-		Temperature: 6.0,
+		Temperature: ptrFloat64(6.0),
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -236,8 +259,8 @@ func TestRecommendationController_SuggestSpotifyPlaylist_SpotifyUnavailable(t *t
 		t.Fatalf("Failed to unmarshal response: %v", err)
 	}
 
-	if response["message"] != "Spotify service is temporarily unavailable" {
-		t.Errorf("Expected message 'Spotify service is temporarily unavailable', got '%s'", response["message"])
+	if response["message"] != "music service is temporarily unavailable" {
+		t.Errorf("Expected message 'music service is temporarily unavailable', got '%s'", response["message"])
 	}
 }
 
@@ -245,15 +268,14 @@ func TestRecommendationController_SuggestSpotifyPlaylist_BeerStyleError(t *testi
 	gin.SetMode(gin.TestMode)
 
 	recommendationService := &mockRecommendationService{
-		shouldError: true,
-		errorMsg:    "failed to find best beer style",
+		err: fmt.Errorf("%w: database unavailable", service.ErrBeerStyleSelection),
 	}
 	validationService := &mockValidationService{}
 
 	controller := NewRecommendationController(recommendationService, validationService)
 
 	requestBody := domain.TemperatureRequest{
-		Temperature: 6.0,
+		Temperature: ptrFloat64(6.0),
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -292,7 +314,7 @@ func TestRecommendationController_SuggestSpotifyPlaylist_GenericError(t *testing
 	controller := NewRecommendationController(recommendationService, validationService)
 
 	requestBody := domain.TemperatureRequest{
-		Temperature: 6.0,
+		Temperature: ptrFloat64(6.0),
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -318,3 +340,108 @@ func TestRecommendationController_SuggestSpotifyPlaylist_GenericError(t *testing
 		t.Errorf("Expected message 'Internal server error', got '%s'", response["message"])
 	}
 }
+
+func TestRecommendationController_SuggestSpotifyPlaylist_Options(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name             string
+		query            string
+		expectedStatus   int
+		expectedStrategy string
+		expectedLimit    int
+		expectedTracks   int
+		expectedSeed     int64
+	}{
+		{"strategy and limit", "?limit=5", http.StatusOK, "containment", 5, 0, 0},
+		{"no limit", "", http.StatusOK, "containment", 0, 0, 0},
+		{"tracks and seed", "?tracks=20&sampling=Seeded&seed=-42", http.StatusOK, "containment", 0, 20, -42},
+		{"limit not a number", "?limit=five", http.StatusBadRequest, "", 0, 0, 0},
+		{"limit too large", "?limit=21", http.StatusBadRequest, "", 0, 0, 0},
+		{"limit zero", "?limit=0", http.StatusBadRequest, "", 0, 0, 0},
+		{"too many tracks", "?tracks=51", http.StatusBadRequest, "", 0, 0, 0},
+		{"seed not a number", "?seed=abc", http.StatusBadRequest, "", 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recommendationService := &mockRecommendationService{}
+			controller := NewRecommendationController(recommendationService, &mockValidationService{})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/"+tt.query, bytes.NewBufferString(`{"temperature": 6.0, "strategy": "containment"}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			controller.SuggestSpotifyPlaylist(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			options := recommendationService.options
+			if options.Strategy != tt.expectedStrategy || options.Limit != tt.expectedLimit || options.Tracks != tt.expectedTracks {
+				t.Errorf("Expected strategy '%s', limit %d and %d tracks, got %+v", tt.expectedStrategy, tt.expectedLimit, tt.expectedTracks, options)
+			}
+			if tt.expectedSeed != 0 && (options.Seed == nil || *options.Seed != tt.expectedSeed || options.Sampling != "seeded") {
+				t.Errorf("Expected seeded sampling with seed %d, got %+v", tt.expectedSeed, options)
+			}
+		})
+	}
+}
+
+func TestRecommendationController_SuggestSpotifyPlaylist_ByLocation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name                string
+		body                string
+		expectedStatus      int
+		expectedTemperature bool
+		expectedQuery       *domain.WeatherQuery
+	}{
+		{"temperature", `{"temperature": 0}`, http.StatusOK, true, nil},
+		{"city", `{"city": "São Paulo"}`, http.StatusOK, false, &domain.WeatherQuery{City: "São Paulo"}},
+		{"coordinates", `{"lat": -23.55, "lon": -46.63}`, http.StatusOK, false, &domain.WeatherQuery{Lat: ptrFloat64(-23.55), Lon: ptrFloat64(-46.63)}},
+		{"empty body is 0°C", `{}`, http.StatusOK, true, nil},
+		{"temperature and city", `{"temperature": 6, "city": "Recife"}`, http.StatusBadRequest, false, nil},
+		{"city and coordinates", `{"city": "Recife", "lat": -8.05, "lon": -34.9}`, http.StatusBadRequest, false, nil},
+		{"latitude alone", `{"lat": -8.05}`, http.StatusBadRequest, false, nil},
+		{"longitude out of range", `{"lat": 10, "lon": 200}`, http.StatusBadRequest, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recommendationService := &mockRecommendationService{}
+			controller := NewRecommendationController(recommendationService, service.NewValidationService(nil, service.NewUpdateService()))
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			controller.SuggestSpotifyPlaylist(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if (recommendationService.temperature != nil) != tt.expectedTemperature {
+				t.Errorf("Expected recommendation by temperature %v, got temperature %v", tt.expectedTemperature, recommendationService.temperature)
+			}
+			if recommendationService.temperature != nil && *recommendationService.temperature != 0 {
+				t.Errorf("Expected 0°C, got %v", *recommendationService.temperature)
+			}
+
+			query := recommendationService.query
+			if (query == nil) != (tt.expectedQuery == nil) {
+				t.Fatalf("Expected query %+v, got %+v", tt.expectedQuery, query)
+			}
+			if query != nil && !reflect.DeepEqual(*query, *tt.expectedQuery) {
+				t.Errorf("Expected query %+v, got %+v", tt.expectedQuery, query)
+			}
+		})
+	}
+}
+
+func ptrFloat64(value float64) *float64 {
+	return &value
+}
@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatusController struct {
+	PlaylistCache service.PlaylistCacheStatsProvider
+	MusicProvider service.MusicProviderStatusProvider
+}
+
+func NewStatusController(playlistCache service.PlaylistCacheStatsProvider, musicProvider service.MusicProviderStatusProvider) *StatusController {
+	return &StatusController{
+		PlaylistCache: playlistCache,
+		MusicProvider: musicProvider,
+	}
+}
+
+func (sc *StatusController) PlaylistCacheStats(c *gin.Context) {
+	if sc.PlaylistCache == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"enabled": false,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled": true,
+		"stats":   sc.PlaylistCache.Stats(),
+	})
+}
+
+func (sc *StatusController) MusicProviderStatus(c *gin.Context) {
+	if sc.MusicProvider == nil {
+		c.JSON(http.StatusOK, domain.MusicProviderStatus{
+			State: domain.MusicProviderStateDisabled,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, sc.MusicProvider.Status())
+}
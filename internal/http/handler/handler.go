@@ -1,29 +1,23 @@
 package handler
 
 import (
-	config "backend-test/internal/cmd/server"
 	"backend-test/internal/http/controller"
-	"backend-test/internal/service"
-	"backend-test/internal/storage/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-var beerController *controller.BeerController
-var recommendationController *controller.RecommendationController
-
-func init() {
-	beerRepo := &repository.BeerRepository{}
-	beerService := service.NewBeerService(beerRepo)
-	validationService := service.NewValidationService(beerService)
-	updateService := service.NewUpdateService()
-
-	spotifyService := config.InitializeSpotifyService()
-
-	recommendationService := service.NewRecommendationService(beerService, spotifyService)
+type Handler struct {
+	beerController           *controller.BeerController
+	recommendationController *controller.RecommendationController
+	statusController         *controller.StatusController
+}
 
-	beerController = controller.NewBeerController(beerService, validationService, updateService)
-	recommendationController = controller.NewRecommendationController(recommendationService, validationService)
+func NewHandler(beerController *controller.BeerController, recommendationController *controller.RecommendationController, statusController *controller.StatusController) *Handler {
+	return &Handler{
+		beerController:           beerController,
+		recommendationController: recommendationController,
+		statusController:         statusController,
+	}
 }
 
 func HealthCheckStatus(c *gin.Context) {
@@ -32,16 +26,29 @@ func HealthCheckStatus(c *gin.Context) {
 	})
 }
 
-func HandleRequests(router *gin.Engine) {
+func (h *Handler) HandleRequests(router *gin.Engine) {
 	api := router.Group("/api")
 	api.GET("/check", HealthCheckStatus)
 
 	beer := api.Group("/beer-styles")
-	beer.GET("/list", beerController.ListAllBeerStyles)
-	beer.POST("/create", beerController.CreateBeerStyle)
-	beer.PUT("/edit/:beerUUID", beerController.UpdateBeerStyle)
-	beer.DELETE("/:beerUUID", beerController.DeleteBeerStyle)
+	beer.GET("/list", h.beerController.ListAllBeerStyles)
+	beer.POST("/create", h.beerController.CreateBeerStyle)
+	beer.POST("/bulk", h.beerController.BulkBeerStyles)
+	beer.GET("/export", h.beerController.ExportBeerStyles)
+	beer.POST("/import", h.beerController.ImportBeerStyles)
+	beer.PUT("/edit/:beerUUID", h.beerController.UpdateBeerStyle)
+	beer.PATCH("/:beerUUID", h.beerController.PatchBeerStyle)
+	beer.GET("/:beerUUID", h.beerController.GetBeerStyle)
+	beer.DELETE("/:beerUUID", h.beerController.DeleteBeerStyle)
+	beer.POST("/:beerUUID/restore", h.beerController.RestoreBeerStyle)
+	beer.GET("/:beerUUID/history", h.beerController.GetBeerStyleHistory)
+	beer.GET("/:beerUUID/playlists", h.beerController.ListBeerStylePlaylists)
+	beer.POST("/:beerUUID/playlists", h.beerController.AddBeerStylePlaylist)
+	beer.PUT("/:beerUUID/playlists/:playlistID", h.beerController.UpdateBeerStylePlaylist)
+	beer.DELETE("/:beerUUID/playlists/:playlistID", h.beerController.RemoveBeerStylePlaylist)
 
 	recommendations := api.Group("/recommendations")
-	recommendations.POST("/suggest", recommendationController.SuggestSpotifyPlaylist)
+	recommendations.POST("/suggest", h.recommendationController.SuggestSpotifyPlaylist)
+	recommendations.GET("/cache/stats", h.statusController.PlaylistCacheStats)
+	recommendations.GET("/provider/status", h.statusController.MusicProviderStatus)
 }
@@ -1,7 +1,9 @@
 package router
 
 import (
+	"backend-test/internal/domain"
 	"net/http"
+	"strings"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -14,8 +16,8 @@ func NewRouter() (router *gin.Engine) {
 func setConfigs(router *gin.Engine) *gin.Engine {
 	router.Use(cors.New(cors.Config{AllowOrigins: []string{"*"},
 		AllowMethods:     []string{http.MethodGet, http.MethodPatch, http.MethodPut, http.MethodPost, http.MethodHead, http.MethodDelete, http.MethodOptions},
-		AllowHeaders:     []string{"Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "accept", "origin", "Cache-Control", "X-Requested-With"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "accept", "origin", "Cache-Control", "X-Requested-With", "If-None-Match", "If-Match", "X-Actor"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
 		AllowCredentials: true}))
 
 	router.Use(func(c *gin.Context) {
@@ -26,5 +28,24 @@ func setConfigs(router *gin.Engine) *gin.Engine {
 		c.Next()
 	})
 
+	router.Use(actorMiddleware)
+
 	return router
 }
+
+const maxActorLength = 255
+
+// actorMiddleware tags the request context with the caller named in the
+// X-Actor header so changes can be attributed in the audit log.
+func actorMiddleware(c *gin.Context) {
+	actor := strings.TrimSpace(c.GetHeader("X-Actor"))
+	if len(actor) > maxActorLength {
+		actor = strings.ToValidUTF8(actor[:maxActorLength], "")
+	}
+
+	if actor != "" {
+		c.Request = c.Request.WithContext(domain.WithActor(c.Request.Context(), actor))
+	}
+
+	c.Next()
+}
@@ -3,54 +3,154 @@ package service
 import (
 	"backend-test/internal/domain"
 	"backend-test/internal/storage/repository"
+	"context"
+	"time"
 )
 
 type BeerService struct {
 	beerRepository repository.BeerRepositoryInterface
+	updateService  UpdateServiceInterface
 }
 
-func NewBeerService(beerRepo repository.BeerRepositoryInterface) *BeerService {
+func NewBeerService(beerRepo repository.BeerRepositoryInterface, updateService UpdateServiceInterface) *BeerService {
 	return &BeerService{
 		beerRepository: beerRepo,
+		updateService:  updateService,
 	}
 }
 
-func (bs BeerService) ListAllBeerStyles() ([]domain.BeerStyle, error) {
-	beerStyles, err := bs.beerRepository.ListAllBeerStyles()
+func (bs BeerService) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
+	beerStyles, err := bs.beerRepository.ListAllBeerStyles(ctx)
 	if err != nil {
 		return []domain.BeerStyle{}, err
 	}
 	return beerStyles, nil
 }
 
-func (bs BeerService) GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle, error) {
-	beerStyle, err := bs.beerRepository.GetBeerStyleByUUID(beerUUID)
+func (bs BeerService) ListBeerStyles(ctx context.Context, params domain.BeerStyleListParams) (domain.BeerStylePage, error) {
+	query := params
+	query.Limit = params.Limit + 1
+
+	beerStyles, err := bs.beerRepository.ListBeerStyles(ctx, query)
+	if err != nil {
+		return domain.BeerStylePage{}, err
+	}
+
+	page := domain.BeerStylePage{
+		BeerStyles: beerStyles,
+		Paging: domain.Paging{
+			Limit: params.Limit,
+			Sort:  params.SortBy,
+			Order: params.SortDir,
+		},
+	}
+
+	if len(beerStyles) > params.Limit {
+		page.BeerStyles = beerStyles[:params.Limit]
+		page.Paging.HasMore = true
+		page.Paging.NextCursor = EncodeBeerStyleCursor(params.SortBy, page.BeerStyles[params.Limit-1])
+	}
+
+	if page.BeerStyles == nil {
+		page.BeerStyles = []domain.BeerStyle{}
+	}
+
+	return page, nil
+}
+
+func (bs BeerService) GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	beerStyle, err := bs.beerRepository.GetBeerStyleByUUID(ctx, beerUUID)
+	if err != nil {
+		return domain.BeerStyle{}, err
+	}
+	return beerStyle, nil
+}
+
+func (bs BeerService) GetBeerStyleByUUIDIncludingDeleted(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	beerStyle, err := bs.beerRepository.GetBeerStyleByUUIDIncludingDeleted(ctx, beerUUID)
 	if err != nil {
 		return domain.BeerStyle{}, err
 	}
 	return beerStyle, nil
 }
 
-func (bs BeerService) UpdateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
-	updatedBeerStyle, err := bs.beerRepository.UpdateBeerStyle(beerStyle)
+func (bs BeerService) UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	updatedBeerStyle, err := bs.beerRepository.UpdateBeerStyle(ctx, beerStyle)
 	if err != nil {
 		return domain.BeerStyle{}, err
 	}
 	return updatedBeerStyle, nil
 }
 
-func (bs BeerService) CreateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
-	createdBeerStyle, err := bs.beerRepository.CreateBeerStyle(beerStyle)
+func (bs BeerService) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	createdBeerStyle, err := bs.beerRepository.CreateBeerStyle(ctx, beerStyle)
 	if err != nil {
 		return domain.BeerStyle{}, err
 	}
 	return createdBeerStyle, nil
 }
 
-func (bs BeerService) DeleteBeerStyle(beerUUID string) error {
-	err := bs.beerRepository.DeleteBeerStyle(beerUUID)
+func (bs BeerService) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
+	err := bs.beerRepository.DeleteBeerStyle(ctx, beerUUID)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func (bs BeerService) RestoreBeerStyle(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	restoredBeerStyle, err := bs.beerRepository.RestoreBeerStyle(ctx, beerUUID)
+	if err != nil {
+		return domain.BeerStyle{}, err
+	}
+	return restoredBeerStyle, nil
+}
+
+// BeerStyleNameKeys returns the database's unique name key for each name.
+// Names that fold to the same key cannot both belong to active styles.
+func (bs BeerService) BeerStyleNameKeys(ctx context.Context, names []string) (map[string]string, error) {
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	return bs.beerRepository.BeerStyleNameKeys(ctx, unique)
+}
+
+func (bs BeerService) ListBeerStyleHistory(ctx context.Context, params domain.BeerStyleHistoryParams) (domain.BeerStyleHistoryPage, error) {
+	query := params
+	query.Limit = params.Limit + 1
+
+	entries, err := bs.beerRepository.ListBeerStyleHistory(ctx, query)
+	if err != nil {
+		return domain.BeerStyleHistoryPage{}, err
+	}
+
+	page := domain.BeerStyleHistoryPage{
+		History: entries,
+		Paging: domain.Paging{
+			Limit: params.Limit,
+			Sort:  "created_at",
+			Order: SortDesc,
+		},
+	}
+
+	if len(entries) > params.Limit {
+		page.History = entries[:params.Limit]
+		page.Paging.HasMore = true
+		page.Paging.NextCursor = EncodeHistoryCursor(page.History[params.Limit-1])
+	}
+
+	if page.History == nil {
+		page.History = []domain.BeerStyleAuditEntry{}
+	}
+
+	return page, nil
+}
+
+func (bs BeerService) PurgeDeletedBeerStyles(ctx context.Context, retention time.Duration) (int64, error) {
+	return bs.beerRepository.PurgeDeletedBeerStyles(ctx, retention)
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/storage/repository"
+	"context"
+	"errors"
+)
+
+// MaxBulkOperations bounds the number of operations in one bulk request.
+const MaxBulkOperations = 500
+
+var errBulkRolledBack = errors.New("bulk operation rolled back")
+
+// ApplyBeerStyleBulk runs the operations in order within one transaction.
+// rejected holds the validation error of each operation, if any; rejected
+// operations are reported as failed and never run. In atomic mode any
+// failure rolls back the whole batch, while in partial mode each operation
+// runs in its own savepoint so only the failing ones are undone.
+func (bs BeerService) ApplyBeerStyleBulk(ctx context.Context, request domain.BeerStyleBulkRequest, rejected []error) (domain.BeerStyleBulkResult, error) {
+	atomic := request.Mode != domain.BulkModePartial
+
+	result := domain.BeerStyleBulkResult{
+		Mode:    request.Mode,
+		Results: make([]domain.BeerStyleBulkItemResult, len(request.Operations)),
+	}
+	for i, operation := range request.Operations {
+		result.Results[i] = domain.BeerStyleBulkItemResult{
+			Index:  i,
+			Op:     operation.Op,
+			UUID:   operation.UUID,
+			Status: domain.BulkItemSkipped,
+		}
+	}
+
+	rejectedAt := func(i int) error {
+		if i < len(rejected) {
+			return rejected[i]
+		}
+		return nil
+	}
+
+	if atomic {
+		anyRejected := false
+		for i := range request.Operations {
+			if err := rejectedAt(i); err != nil {
+				result.Results[i].Status = domain.BulkItemFailed
+				result.Results[i].Err = err
+				anyRejected = true
+			}
+		}
+		if anyRejected {
+			return countBulkResults(result), nil
+		}
+	}
+
+	err := bs.beerRepository.WithTransaction(ctx, func(tx repository.BeerRepositoryInterface) error {
+		for i, operation := range request.Operations {
+			item := &result.Results[i]
+
+			if err := rejectedAt(i); err != nil {
+				item.Status = domain.BulkItemFailed
+				item.Err = err
+				continue
+			}
+
+			var beerStyle *domain.BeerStyle
+			run := func() error {
+				var err error
+				beerStyle, err = bs.applyBulkOperation(ctx, tx, operation)
+				return err
+			}
+
+			var err error
+			if atomic {
+				err = run()
+			} else {
+				err = tx.WithSavepoint(ctx, run)
+			}
+
+			if err != nil {
+				item.Status = domain.BulkItemFailed
+				item.Err = err
+				if atomic {
+					return errBulkRolledBack
+				}
+				continue
+			}
+
+			item.Status = domain.BulkItemSucceeded
+			item.Data = beerStyle
+			if beerStyle != nil {
+				item.UUID = beerStyle.UUID
+			}
+		}
+		return nil
+	})
+
+	if errors.Is(err, errBulkRolledBack) {
+		for i := range result.Results {
+			if result.Results[i].Status == domain.BulkItemSucceeded {
+				result.Results[i].Status = domain.BulkItemRolledBack
+				result.Results[i].Data = nil
+			}
+		}
+		return countBulkResults(result), nil
+	}
+	if err != nil {
+		return domain.BeerStyleBulkResult{}, err
+	}
+
+	result.Committed = true
+	return countBulkResults(result), nil
+}
+
+func (bs BeerService) applyBulkOperation(ctx context.Context, tx repository.BeerRepositoryInterface, operation domain.BeerStyleBulkOperation) (*domain.BeerStyle, error) {
+	switch operation.Op {
+	case domain.BulkOperationCreate:
+		if operation.Name == nil || operation.TempMin == nil || operation.TempMax == nil {
+			return nil, domain.NewValidationError("name, temp_min and temp_max are required to create a beer style")
+		}
+
+		created, err := tx.CreateBeerStyle(ctx, domain.BeerStyle{
+			Name:    *operation.Name,
+			TempMin: *operation.TempMin,
+			TempMax: *operation.TempMax,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &created, nil
+
+	case domain.BulkOperationUpdate:
+		current, err := currentForBulkOperation(ctx, tx, operation)
+		if err != nil {
+			return nil, err
+		}
+
+		updates := domain.BeerStyleUpdateRequest{Name: operation.Name, TempMin: operation.TempMin, TempMax: operation.TempMax}
+		if !bs.updateService.ApplyBeerStyleUpdates(&current, updates) {
+			return &current, nil
+		}
+
+		updated, err := tx.UpdateBeerStyle(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		return &updated, nil
+
+	case domain.BulkOperationDelete:
+		if _, err := currentForBulkOperation(ctx, tx, operation); err != nil {
+			return nil, err
+		}
+		return nil, tx.DeleteBeerStyle(ctx, operation.UUID)
+
+	default:
+		return nil, domain.NewValidationError("unsupported bulk operation '%s'", operation.Op)
+	}
+}
+
+func currentForBulkOperation(ctx context.Context, tx repository.BeerRepositoryInterface, operation domain.BeerStyleBulkOperation) (domain.BeerStyle, error) {
+	current, err := tx.GetBeerStyleByUUID(ctx, operation.UUID)
+	if err != nil {
+		return domain.BeerStyle{}, err
+	}
+
+	if operation.Version != nil && *operation.Version != current.Version {
+		return domain.BeerStyle{}, domain.NewPreconditionFailedError("beer style was modified by another request")
+	}
+
+	return current, nil
+}
+
+func countBulkResults(result domain.BeerStyleBulkResult) domain.BeerStyleBulkResult {
+	for _, item := range result.Results {
+		switch item.Status {
+		case domain.BulkItemSucceeded:
+			result.Succeeded++
+		case domain.BulkItemFailed:
+			result.Failed++
+		}
+	}
+	return result
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"backend-test/internal/storage/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vingarcia/ksql"
+)
+
+type bulkDB struct {
+	statements []string
+	txErr      error
+}
+
+func (b *bulkDB) mock() ksql.Mock {
+	return ksql.Mock{
+		QueryOneFn: func(ctx context.Context, record interface{}, query string, params ...interface{}) error {
+			beerStyle := record.(*domain.BeerStyle)
+			switch {
+			case strings.Contains(query, "INSERT INTO beer_styles"):
+				*beerStyle = domain.BeerStyle{UUID: "new-" + params[0].(string), Name: params[0].(string), Version: 1}
+				return nil
+			case strings.Contains(query, "SET name"):
+				*beerStyle = domain.BeerStyle{UUID: params[3].(string), Name: params[0].(string), TempMin: params[1].(float64), TempMax: params[2].(float64), Version: 2}
+				return nil
+			case params[0] == "missing":
+				return sql.ErrNoRows
+			default:
+				*beerStyle = domain.BeerStyle{UUID: params[0].(string), Name: "IPA", TempMin: 7, TempMax: 10, Version: 1}
+				return nil
+			}
+		},
+		ExecFn: func(ctx context.Context, query string, params ...interface{}) (ksql.Result, error) {
+			if strings.Contains(query, "SAVEPOINT") {
+				b.statements = append(b.statements, query)
+			}
+			return ksql.NewMockResult(0, 1), nil
+		},
+		TransactionFn: func(ctx context.Context, fn func(db ksql.Provider) error) error {
+			b.txErr = fn(b.mock())
+			return b.txErr
+		},
+	}
+}
+
+func bulkTestOperations() []domain.BeerStyleBulkOperation {
+	name := func(value string) *string { return &value }
+	temp := func(value float64) *float64 { return &value }
+
+	return []domain.BeerStyleBulkOperation{
+		{Op: domain.BulkOperationCreate, Name: name("Lager"), TempMin: temp(2), TempMax: temp(6)},
+		{Op: domain.BulkOperationDelete, UUID: "missing"},
+		{Op: domain.BulkOperationUpdate, UUID: "uuid-1", TempMax: temp(12)},
+	}
+}
+
+func TestBeerService_ApplyBeerStyleBulk_Partial(t *testing.T) {
+	db := &bulkDB{}
+	beerService := NewBeerService(repository.NewBeerRepository(db.mock(), 0), NewUpdateService())
+
+	result, err := beerService.ApplyBeerStyleBulk(context.Background(), domain.BeerStyleBulkRequest{
+		Mode:       domain.BulkModePartial,
+		Operations: bulkTestOperations(),
+	}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !result.Committed || result.Succeeded != 2 || result.Failed != 1 {
+		t.Fatalf("Expected 2 succeeded and 1 failed in a committed batch, got %+v", result)
+	}
+
+	statuses := []string{result.Results[0].Status, result.Results[1].Status, result.Results[2].Status}
+	if statuses[0] != domain.BulkItemSucceeded || statuses[1] != domain.BulkItemFailed || statuses[2] != domain.BulkItemSucceeded {
+		t.Errorf("Unexpected statuses %v", statuses)
+	}
+	if !errors.Is(result.Results[1].Err, domain.ErrNotFound) {
+		t.Errorf("Expected not found for the missing style, got %v", result.Results[1].Err)
+	}
+	if result.Results[0].UUID != "new-Lager" || result.Results[2].Data == nil || result.Results[2].Data.TempMax != 12 {
+		t.Errorf("Expected created and updated styles in the results, got %+v", result.Results)
+	}
+
+	rollbacks := 0
+	for _, statement := range db.statements {
+		if strings.HasPrefix(statement, "ROLLBACK TO") {
+			rollbacks++
+		}
+	}
+	if len(db.statements) != 6 || rollbacks != 1 {
+		t.Errorf("Expected one savepoint per operation and one rollback, got %v", db.statements)
+	}
+}
+
+func TestBeerService_ApplyBeerStyleBulk_Atomic(t *testing.T) {
+	db := &bulkDB{}
+	beerService := NewBeerService(repository.NewBeerRepository(db.mock(), 0), NewUpdateService())
+
+	result, err := beerService.ApplyBeerStyleBulk(context.Background(), domain.BeerStyleBulkRequest{
+		Mode:       domain.BulkModeAtomic,
+		Operations: bulkTestOperations(),
+	}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Committed || db.txErr == nil {
+		t.Fatalf("Expected the transaction to roll back, got %+v", result)
+	}
+
+	statuses := []string{result.Results[0].Status, result.Results[1].Status, result.Results[2].Status}
+	if statuses[0] != domain.BulkItemRolledBack || statuses[1] != domain.BulkItemFailed || statuses[2] != domain.BulkItemSkipped {
+		t.Errorf("Unexpected statuses %v", statuses)
+	}
+	if result.Succeeded != 0 || result.Failed != 1 || result.Results[0].Data != nil {
+		t.Errorf("Expected no successes to be reported, got %+v", result)
+	}
+	if len(db.statements) != 0 {
+		t.Errorf("Expected no savepoints in atomic mode, got %v", db.statements)
+	}
+}
+
+func TestBeerService_ApplyBeerStyleBulk_AtomicRejected(t *testing.T) {
+	db := &bulkDB{}
+	beerService := NewBeerService(repository.NewBeerRepository(db.mock(), 0), NewUpdateService())
+
+	rejected := []error{nil, domain.NewValidationError("name is required"), nil}
+	result, err := beerService.ApplyBeerStyleBulk(context.Background(), domain.BeerStyleBulkRequest{
+		Mode:       domain.BulkModeAtomic,
+		Operations: bulkTestOperations(),
+	}, rejected)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Committed || result.Failed != 1 || result.Results[0].Status != domain.BulkItemSkipped {
+		t.Errorf("Expected nothing to run, got %+v", result)
+	}
+	if db.txErr != nil {
+		t.Errorf("Expected no transaction to be opened, got %v", db.txErr)
+	}
+}
+
+func TestValidationService_ValidateBulkOperations(t *testing.T) {
+	existing := []domain.BeerStyle{
+		{UUID: "0f8fad5b-d9cb-469f-a165-70867728950e", Name: "IPA", TempMin: 7, TempMax: 10, Version: 2},
+		{UUID: "7c9e6679-7425-40de-944b-e07fc1f90ae7", Name: "Stout", TempMin: 8, TempMax: 12, Version: 1},
+	}
+	name := func(value string) *string { return &value }
+	temp := func(value float64) *float64 { return &value }
+	version := func(value int64) *int64 { return &value }
+
+	tests := []struct {
+		name       string
+		operations []domain.BeerStyleBulkOperation
+		expected   []error
+	}{
+		{
+			"duplicate names within the batch",
+			[]domain.BeerStyleBulkOperation{
+				{Op: "create", Name: name("Lager"), TempMin: temp(2), TempMax: temp(6)},
+				{Op: "create", Name: name("Lager"), TempMin: temp(2), TempMax: temp(6)},
+			},
+			[]error{nil, domain.ErrConflict},
+		},
+		{
+			"name taken by an existing style",
+			[]domain.BeerStyleBulkOperation{
+				{Op: "create", Name: name("IPA"), TempMin: temp(2), TempMax: temp(6)},
+				{Op: "update", UUID: existing[1].UUID, Name: name("IPA")},
+			},
+			[]error{domain.ErrConflict, domain.ErrConflict},
+		},
+		{
+			"names differing only in case or accents",
+			[]domain.BeerStyleBulkOperation{
+				{Op: "create", Name: name("ipa"), TempMin: temp(2), TempMax: temp(6)},
+				{Op: "create", Name: name("Märzen"), TempMin: temp(4), TempMax: temp(7)},
+				{Op: "create", Name: name("MARZEN"), TempMin: temp(4), TempMax: temp(7)},
+				{Op: "update", UUID: existing[1].UUID, Name: name("STOUT")},
+			},
+			[]error{domain.ErrConflict, nil, domain.ErrConflict, nil},
+		},
+		{
+			"letters without a decomposition",
+			[]domain.BeerStyleBulkOperation{
+				{Op: "create", Name: name("Øl"), TempMin: temp(4), TempMax: temp(7)},
+				{Op: "create", Name: name("OL"), TempMin: temp(4), TempMax: temp(7)},
+				{Op: "create", Name: name("Weißbier"), TempMin: temp(4), TempMax: temp(7)},
+				{Op: "create", Name: name("WEISSBIER"), TempMin: temp(4), TempMax: temp(7)},
+			},
+			[]error{nil, domain.ErrConflict, nil, domain.ErrConflict},
+		},
+		{
+			"name freed earlier in the batch",
+			[]domain.BeerStyleBulkOperation{
+				{Op: "update", UUID: existing[0].UUID, Name: name("West Coast IPA")},
+				{Op: "create", Name: name("IPA"), TempMin: temp(2), TempMax: temp(6)},
+				{Op: "delete", UUID: existing[1].UUID},
+				{Op: "create", Name: name("Stout"), TempMin: temp(8), TempMax: temp(12)},
+			},
+			[]error{nil, nil, nil, nil},
+		},
+		{
+			"invalid operations",
+			[]domain.BeerStyleBulkOperation{
+				{Op: "create", Name: name("Lager")},
+				{Op: "create", Name: name("Lager"), TempMin: temp(6), TempMax: temp(2)},
+				{Op: "update", UUID: existing[0].UUID, TempMin: temp(11)},
+				{Op: "update", UUID: existing[0].UUID, Name: name("")},
+				{Op: "upsert"},
+				{Op: "delete", UUID: "not-a-uuid"},
+			},
+			[]error{domain.ErrValidation, domain.ErrValidation, domain.ErrValidation, domain.ErrValidation, domain.ErrValidation, domain.ErrValidation},
+		},
+		{
+			"missing styles and versions",
+			[]domain.BeerStyleBulkOperation{
+				{Op: "delete", UUID: "9b2d5c3e-5f0a-4c1e-9a7f-2f6c1d3b4a5e"},
+				{Op: "update", UUID: existing[0].UUID, TempMax: temp(11), Version: version(1)},
+				{Op: "update", UUID: existing[0].UUID, TempMax: temp(11), Version: version(2)},
+				{Op: "delete", UUID: existing[0].UUID, Version: version(3)},
+				{Op: "update", UUID: existing[0].UUID, TempMax: temp(9)},
+			},
+			[]error{domain.ErrNotFound, domain.ErrPreconditionFailed, nil, nil, domain.ErrNotFound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validationService := NewValidationService(&stubBeerService{beers: existing}, NewUpdateService())
+
+			errs, err := validationService.ValidateBulkOperations(context.Background(), tt.operations)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			for i, expected := range tt.expected {
+				if expected == nil && errs[i] != nil {
+					t.Errorf("operation %d: expected no error, got %v", i, errs[i])
+				}
+				if expected != nil && !errors.Is(errs[i], expected) {
+					t.Errorf("operation %d: expected %v, got %v", i, expected, errs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidationService_ValidateBulkOperations_UsesDatabaseNameKeys(t *testing.T) {
+	existing := []domain.BeerStyle{{UUID: "0f8fad5b-d9cb-469f-a165-70867728950e", Name: "Ol", TempMin: 4, TempMax: 7}}
+	name := func(value string) *string { return &value }
+	temp := func(value float64) *float64 { return &value }
+
+	operations := []domain.BeerStyleBulkOperation{
+		{Op: "create", Name: name("Øl"), TempMin: temp(4), TempMax: temp(7)},
+	}
+
+	tests := []struct {
+		name     string
+		nameKeys map[string]string
+		expected error
+	}{
+		{"database folds the names together", map[string]string{"Ol": "ol", "Øl": "ol"}, domain.ErrConflict},
+		{"database keeps the names apart", map[string]string{"Ol": "ol", "Øl": "øl"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validationService := NewValidationService(&stubBeerService{beers: existing, nameKeys: tt.nameKeys}, NewUpdateService())
+
+			errs, err := validationService.ValidateBulkOperations(context.Background(), operations)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if tt.expected == nil && errs[0] != nil {
+				t.Errorf("Expected no error, got %v", errs[0])
+			}
+			if tt.expected != nil && !errors.Is(errs[0], tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, errs[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,500 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxImportRows bounds the number of records in one import file.
+	MaxImportRows = 5000
+
+	exportChunkSize = 100
+)
+
+var beerStyleExportColumns = []string{"uuid", "name", "temp_min", "temp_max", "version", "created_at", "updated_at"}
+
+// ExportBeerStyles streams the active catalog to fn in name order.
+func (bs BeerService) ExportBeerStyles(ctx context.Context, fn func([]domain.BeerStyle) error) error {
+	return bs.beerRepository.StreamBeerStyles(ctx, exportChunkSize, fn)
+}
+
+// CatalogContentType returns the media type used for a catalog format.
+func CatalogContentType(format string) string {
+	switch format {
+	case domain.CatalogFormatCSV:
+		return "text/csv; charset=utf-8"
+	case domain.CatalogFormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// BeerStyleExportWriter encodes beer styles one at a time, so an export can
+// be written while it is read from the database. Close terminates the
+// document and flushes anything still buffered.
+type BeerStyleExportWriter interface {
+	Write(beerStyle domain.BeerStyle) error
+	Close() error
+}
+
+func NewBeerStyleExportWriter(format string, w io.Writer) (BeerStyleExportWriter, error) {
+	switch format {
+	case domain.CatalogFormatCSV:
+		return &csvExportWriter{w: csv.NewWriter(w)}, nil
+	case domain.CatalogFormatJSON:
+		return &jsonExportWriter{w: w}, nil
+	case domain.CatalogFormatNDJSON:
+		return &ndjsonExportWriter{encoder: json.NewEncoder(w)}, nil
+	default:
+		return nil, unsupportedCatalogFormat(format)
+	}
+}
+
+type csvExportWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (cw *csvExportWriter) Write(beerStyle domain.BeerStyle) error {
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+
+	return cw.w.Write([]string{
+		beerStyle.UUID,
+		beerStyle.Name,
+		strconv.FormatFloat(beerStyle.TempMin, 'f', -1, 64),
+		strconv.FormatFloat(beerStyle.TempMax, 'f', -1, 64),
+		strconv.FormatInt(beerStyle.Version, 10),
+		beerStyle.CreatedAt.Format(time.RFC3339),
+		beerStyle.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+func (cw *csvExportWriter) Close() error {
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvExportWriter) writeHeader() error {
+	if cw.headerWritten {
+		return nil
+	}
+	cw.headerWritten = true
+	return cw.w.Write(beerStyleExportColumns)
+}
+
+type jsonExportWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (jw *jsonExportWriter) Write(beerStyle domain.BeerStyle) error {
+	separator := ","
+	if !jw.written {
+		separator = "["
+		jw.written = true
+	}
+
+	raw, err := json.Marshal(beerStyle)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(jw.w, separator+string(raw))
+	return err
+}
+
+func (jw *jsonExportWriter) Close() error {
+	closing := "]\n"
+	if !jw.written {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(jw.w, closing)
+	return err
+}
+
+type ndjsonExportWriter struct {
+	encoder *json.Encoder
+}
+
+func (nw *ndjsonExportWriter) Write(beerStyle domain.BeerStyle) error {
+	return nw.encoder.Encode(beerStyle)
+}
+
+func (nw *ndjsonExportWriter) Close() error {
+	return nil
+}
+
+// importRecord accepts the export shape; columns other than name, temp_min
+// and temp_max are ignored so an export can be imported back as-is.
+type importRecord struct {
+	Name    *string  `json:"name"`
+	TempMin *float64 `json:"temp_min"`
+	TempMax *float64 `json:"temp_max"`
+}
+
+// DecodeBeerStyleImport reads an import file. A structural problem, such as
+// a missing CSV column or a malformed JSON array, fails the whole file; a bad
+// record only marks its own row as invalid.
+func DecodeBeerStyleImport(format string, r io.Reader) ([]domain.BeerStyleImportRow, error) {
+	var (
+		rows []domain.BeerStyleImportRow
+		err  error
+	)
+
+	switch format {
+	case domain.CatalogFormatCSV:
+		rows, err = decodeCSVImport(r)
+	case domain.CatalogFormatJSON:
+		rows, err = decodeJSONImport(r)
+	case domain.CatalogFormatNDJSON:
+		rows, err = decodeNDJSONImport(r)
+	default:
+		return nil, unsupportedCatalogFormat(format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, domain.NewValidationError("import file has no records")
+	}
+	return rows, nil
+}
+
+func decodeCSVImport(r io.Reader) ([]domain.BeerStyleImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, domain.NewValidationError("invalid CSV header: %v", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))] = i
+	}
+	for _, required := range []string{"name", "temp_min", "temp_max"} {
+		if _, ok := columns[required]; !ok {
+			return nil, domain.NewValidationError("CSV header must include '%s'", required)
+		}
+	}
+
+	var rows []domain.BeerStyleImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, domain.NewValidationError("invalid CSV: %v", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(rows) == MaxImportRows {
+			return nil, tooManyImportRows()
+		}
+		rows = append(rows, csvImportRow(line, record, columns))
+	}
+}
+
+func csvImportRow(line int, record []string, columns map[string]int) domain.BeerStyleImportRow {
+	row := domain.BeerStyleImportRow{Row: line}
+
+	field := func(column string) string {
+		if i := columns[column]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	row.Name = field("name")
+	if row.Name == "" {
+		row.Err = domain.NewValidationError("name is required")
+		return row
+	}
+
+	for _, column := range []string{"temp_min", "temp_max"} {
+		value, err := strconv.ParseFloat(field(column), 64)
+		if err != nil {
+			row.Err = domain.NewValidationError("%s must be a number", column)
+			return row
+		}
+		if column == "temp_min" {
+			row.TempMin = value
+		} else {
+			row.TempMax = value
+		}
+	}
+
+	return row
+}
+
+func decodeJSONImport(r io.Reader) ([]domain.BeerStyleImportRow, error) {
+	decoder := json.NewDecoder(r)
+
+	token, err := decoder.Token()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if delim, ok := token.(json.Delim); err != nil || !ok || delim != '[' {
+		return nil, domain.NewValidationError("JSON import must be an array of beer styles")
+	}
+
+	var rows []domain.BeerStyleImportRow
+	for decoder.More() {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, domain.NewValidationError("invalid JSON: %v", err)
+		}
+		if len(rows) == MaxImportRows {
+			return nil, tooManyImportRows()
+		}
+		rows = append(rows, jsonImportRow(len(rows)+1, raw))
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, domain.NewValidationError("invalid JSON: %v", err)
+	}
+	return rows, nil
+}
+
+func decodeNDJSONImport(r io.Reader) ([]domain.BeerStyleImportRow, error) {
+	scanner := bufio.NewScanner(r)
+
+	var rows []domain.BeerStyleImportRow
+	for line := 1; scanner.Scan(); line++ {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+		if len(rows) == MaxImportRows {
+			return nil, tooManyImportRows()
+		}
+		rows = append(rows, jsonImportRow(line, json.RawMessage(raw)))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, domain.NewValidationError("invalid NDJSON: %v", err)
+	}
+	return rows, nil
+}
+
+func jsonImportRow(row int, raw json.RawMessage) domain.BeerStyleImportRow {
+	var record importRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return domain.BeerStyleImportRow{Row: row, Err: domain.NewValidationError("invalid record")}
+	}
+
+	result := domain.BeerStyleImportRow{Row: row}
+	if record.Name != nil {
+		result.Name = strings.TrimSpace(*record.Name)
+	}
+
+	switch {
+	case result.Name == "":
+		result.Err = domain.NewValidationError("name is required")
+	case record.TempMin == nil:
+		result.Err = domain.NewValidationError("temp_min is required")
+	case record.TempMax == nil:
+		result.Err = domain.NewValidationError("temp_max is required")
+	default:
+		result.TempMin = *record.TempMin
+		result.TempMax = *record.TempMax
+	}
+
+	return result
+}
+
+// BeerStyleImportPlan turns import rows into bulk operations matched by name
+// against the current catalog, and folds validation and execution outcomes
+// back into a per-row report.
+type BeerStyleImportPlan struct {
+	Operations []domain.BeerStyleBulkOperation
+	items      []domain.BeerStyleImportItem
+	// itemIndex maps each operation to the row item it came from.
+	itemIndex []int
+}
+
+// ImportNameCandidates lists the names whose database keys
+// PlanBeerStyleImport needs: the catalog's and the file's.
+func ImportNameCandidates(rows []domain.BeerStyleImportRow, existing []domain.BeerStyle) []string {
+	names := make([]string, 0, len(rows)+len(existing))
+	for _, beerStyle := range existing {
+		names = append(names, beerStyle.Name)
+	}
+	for _, row := range rows {
+		if row.Err == nil {
+			names = append(names, row.Name)
+		}
+	}
+	return names
+}
+
+// PlanBeerStyleImport creates rows whose name is new and updates rows whose
+// name exists with a different spelling or temperatures. Names match by the
+// keys the database index gives them (see ImportNameCandidates), ignoring
+// case and accents. Rows identical to the catalog are left alone, and a name
+// repeated in the file is a conflict.
+func PlanBeerStyleImport(rows []domain.BeerStyleImportRow, existing []domain.BeerStyle, keys map[string]string) *BeerStyleImportPlan {
+	nameKey := func(name string) string {
+		if key, ok := keys[name]; ok {
+			return key
+		}
+		return name
+	}
+
+	byName := make(map[string]domain.BeerStyle, len(existing))
+	for _, beerStyle := range existing {
+		byName[nameKey(beerStyle.Name)] = beerStyle
+	}
+
+	plan := &BeerStyleImportPlan{}
+	seen := make(map[string]int, len(rows))
+
+	for _, row := range rows {
+		item := domain.BeerStyleImportItem{Row: row.Row, Name: row.Name}
+
+		if row.Err != nil {
+			item.Action = domain.ImportActionInvalid
+			item.Err = row.Err
+			plan.items = append(plan.items, item)
+			continue
+		}
+
+		key := nameKey(row.Name)
+		if firstRow, ok := seen[key]; ok {
+			item.Action = domain.ImportActionConflict
+			item.Err = domain.NewConflictError("name '%s' already appears on row %d", row.Name, firstRow)
+			plan.items = append(plan.items, item)
+			continue
+		}
+		seen[key] = row.Row
+
+		name, tempMin, tempMax := row.Name, row.TempMin, row.TempMax
+		operation := domain.BeerStyleBulkOperation{Op: domain.BulkOperationCreate, Name: &name, TempMin: &tempMin, TempMax: &tempMax}
+		item.Action = domain.ImportActionCreate
+
+		if current, ok := byName[key]; ok {
+			item.UUID = current.UUID
+			if current.Name == row.Name && current.TempMin == row.TempMin && current.TempMax == row.TempMax {
+				item.Action = domain.ImportActionUnchanged
+				plan.items = append(plan.items, item)
+				continue
+			}
+
+			version := current.Version
+			operation = domain.BeerStyleBulkOperation{Op: domain.BulkOperationUpdate, UUID: current.UUID, TempMin: &tempMin, TempMax: &tempMax, Version: &version}
+			if current.Name != row.Name {
+				operation.Name = &name
+			}
+			item.Action = domain.ImportActionUpdate
+		}
+
+		plan.itemIndex = append(plan.itemIndex, len(plan.items))
+		plan.Operations = append(plan.Operations, operation)
+		plan.items = append(plan.items, item)
+	}
+
+	return plan
+}
+
+// Reject records the validation error of each operation.
+func (p *BeerStyleImportPlan) Reject(errs []error) {
+	for i, err := range errs {
+		if err != nil && i < len(p.itemIndex) {
+			p.fail(p.itemIndex[i], err)
+		}
+	}
+}
+
+// Apply records the outcome of running the plan's operations.
+func (p *BeerStyleImportPlan) Apply(result domain.BeerStyleBulkResult) {
+	for _, itemResult := range result.Results {
+		if itemResult.Index >= len(p.itemIndex) {
+			continue
+		}
+		itemIndex := p.itemIndex[itemResult.Index]
+
+		if itemResult.Err != nil {
+			p.fail(itemIndex, itemResult.Err)
+			continue
+		}
+		if itemResult.Data != nil {
+			p.items[itemIndex].UUID = itemResult.Data.UUID
+		}
+	}
+}
+
+// Failed returns the first row that cannot be imported, if any.
+func (p *BeerStyleImportPlan) Failed() *domain.BeerStyleImportItem {
+	for i := range p.items {
+		if p.items[i].Err != nil {
+			return &p.items[i]
+		}
+	}
+	return nil
+}
+
+func (p *BeerStyleImportPlan) Report(dryRun, committed bool) domain.BeerStyleImportReport {
+	report := domain.BeerStyleImportReport{
+		DryRun:    dryRun,
+		Committed: committed,
+		Items:     p.items,
+	}
+
+	for _, item := range p.items {
+		switch item.Action {
+		case domain.ImportActionCreate:
+			report.Creates++
+		case domain.ImportActionUpdate:
+			report.Updates++
+		case domain.ImportActionUnchanged:
+			report.Unchanged++
+		case domain.ImportActionConflict:
+			report.Conflicts++
+		case domain.ImportActionInvalid:
+			report.Invalid++
+		}
+	}
+
+	return report
+}
+
+// fail marks an item as a conflict when it clashes with other data and as
+// invalid otherwise.
+func (p *BeerStyleImportPlan) fail(itemIndex int, err error) {
+	item := &p.items[itemIndex]
+	item.Err = err
+	item.Action = domain.ImportActionInvalid
+	if errors.Is(err, domain.ErrConflict) || errors.Is(err, domain.ErrPreconditionFailed) || errors.Is(err, domain.ErrNotFound) {
+		item.Action = domain.ImportActionConflict
+	}
+}
+
+func unsupportedCatalogFormat(format string) error {
+	return domain.NewValidationError("format must be one of '%s', '%s' or '%s', got '%s'",
+		domain.CatalogFormatCSV, domain.CatalogFormatJSON, domain.CatalogFormatNDJSON, format)
+}
+
+func tooManyImportRows() error {
+	return domain.NewValidationError("import file has more than %d records", MaxImportRows)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeBeerStyleImport(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		input    string
+		expected []domain.BeerStyleImportRow
+		errKind  error
+	}{
+		{
+			"csv with extra columns",
+			"csv",
+			"uuid,Name,temp_min,temp_max\nx,IPA,7,10\ny,,1,2\nz,Stout,cold,12\n",
+			[]domain.BeerStyleImportRow{{Row: 2, Name: "IPA", TempMin: 7, TempMax: 10}, {Row: 3, Err: domain.ErrValidation}, {Row: 4, Name: "Stout", Err: domain.ErrValidation}},
+			nil,
+		},
+		{"csv without temp_max", "csv", "name,temp_min\nIPA,7\n", nil, domain.ErrValidation},
+		{"csv header only", "csv", "name,temp_min,temp_max\n", nil, domain.ErrValidation},
+		{
+			"json array",
+			"json",
+			`[{"name": "IPA", "temp_min": 7, "temp_max": 10, "version": 3}, {"name": "Stout", "temp_min": "cold"}, {"name": "Lager", "temp_min": 2}]`,
+			[]domain.BeerStyleImportRow{{Row: 1, Name: "IPA", TempMin: 7, TempMax: 10}, {Row: 2, Err: domain.ErrValidation}, {Row: 3, Name: "Lager", Err: domain.ErrValidation}},
+			nil,
+		},
+		{"json object", "json", `{"name": "IPA"}`, nil, domain.ErrValidation},
+		{"truncated json", "json", `[{"name": "IPA", "temp_min": 7, "temp_max": 10}`, nil, domain.ErrValidation},
+		{
+			"ndjson with blank and broken lines",
+			"ndjson",
+			"{\"name\": \"IPA\", \"temp_min\": 7, \"temp_max\": 10}\n\n{not json}\n",
+			[]domain.BeerStyleImportRow{{Row: 1, Name: "IPA", TempMin: 7, TempMax: 10}, {Row: 3, Err: domain.ErrValidation}},
+			nil,
+		},
+		{"unknown format", "xml", "<styles/>", nil, domain.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := DecodeBeerStyleImport(tt.format, strings.NewReader(tt.input))
+			if tt.errKind != nil {
+				if !errors.Is(err, tt.errKind) {
+					t.Fatalf("Expected error kind %v, got %v", tt.errKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(rows) != len(tt.expected) {
+				t.Fatalf("Expected %d rows, got %+v", len(tt.expected), rows)
+			}
+			for i, expected := range tt.expected {
+				row := rows[i]
+				if expected.Err != nil {
+					if row.Row != expected.Row || !errors.Is(row.Err, expected.Err) {
+						t.Errorf("row %d: expected an invalid row %d, got %+v", i, expected.Row, row)
+					}
+					continue
+				}
+				if row.Err != nil || row.Row != expected.Row || row.Name != expected.Name || row.TempMin != expected.TempMin || row.TempMax != expected.TempMax {
+					t.Errorf("row %d: expected %+v, got %+v", i, expected, row)
+				}
+			}
+		})
+	}
+}
+
+func TestBeerStyleExportWriter_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	beerStyles := []domain.BeerStyle{
+		{UUID: "uuid-1", Name: "IPA, West Coast", TempMin: 7, TempMax: 10.5, Version: 2, CreatedAt: createdAt, UpdatedAt: createdAt},
+		{UUID: "uuid-2", Name: `Imperial "Stout"`, TempMin: -1, TempMax: 13, Version: 1, CreatedAt: createdAt, UpdatedAt: createdAt},
+	}
+
+	for _, format := range []string{domain.CatalogFormatCSV, domain.CatalogFormatJSON, domain.CatalogFormatNDJSON} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := NewBeerStyleExportWriter(format, &buf)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			for _, beerStyle := range beerStyles {
+				if err := writer.Write(beerStyle); err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			rows, err := DecodeBeerStyleImport(format, &buf)
+			if err != nil {
+				t.Fatalf("Expected export to be importable, got %v", err)
+			}
+			if len(rows) != len(beerStyles) {
+				t.Fatalf("Expected %d rows, got %d", len(beerStyles), len(rows))
+			}
+			for i, row := range rows {
+				if row.Err != nil || row.Name != beerStyles[i].Name || row.TempMin != beerStyles[i].TempMin || row.TempMax != beerStyles[i].TempMax {
+					t.Errorf("row %d: expected %+v, got %+v", i, beerStyles[i], row)
+				}
+			}
+		})
+	}
+
+	var empty bytes.Buffer
+	writer, _ := NewBeerStyleExportWriter(domain.CatalogFormatJSON, &empty)
+	if err := writer.Close(); err != nil || strings.TrimSpace(empty.String()) != "[]" {
+		t.Errorf("Expected an empty JSON array, got %q (%v)", empty.String(), err)
+	}
+}
+
+func TestPlanBeerStyleImport(t *testing.T) {
+	existing := []domain.BeerStyle{
+		{UUID: "uuid-ipa", Name: "IPA", TempMin: 7, TempMax: 10, Version: 4},
+		{UUID: "uuid-stout", Name: "Stout", TempMin: 8, TempMax: 12, Version: 1},
+	}
+	rows := []domain.BeerStyleImportRow{
+		{Row: 2, Name: "IPA", TempMin: 7, TempMax: 10},
+		{Row: 3, Name: "Stout", TempMin: 9, TempMax: 12},
+		{Row: 4, Name: "Lager", TempMin: 2, TempMax: 6},
+		{Row: 5, Name: "Lager", TempMin: 2, TempMax: 6},
+		{Row: 6, Err: domain.NewValidationError("name is required")},
+		{Row: 7, Name: "Pilsner", TempMin: 6, TempMax: 2},
+	}
+
+	plan := PlanBeerStyleImport(rows, existing, foldedNameKeys(ImportNameCandidates(rows, existing)))
+
+	if len(plan.Operations) != 3 {
+		t.Fatalf("Expected 3 operations, got %+v", plan.Operations)
+	}
+	update := plan.Operations[0]
+	if update.Op != domain.BulkOperationUpdate || update.UUID != "uuid-stout" || *update.Version != 1 || update.Name != nil {
+		t.Errorf("Expected a versioned update of Stout, got %+v", update)
+	}
+	if plan.Operations[1].Op != domain.BulkOperationCreate || *plan.Operations[1].Name != "Lager" {
+		t.Errorf("Expected Lager to be created, got %+v", plan.Operations[1])
+	}
+
+	plan.Reject([]error{nil, nil, domain.NewValidationError("minimum temperature must be less than maximum temperature")})
+	plan.Apply(domain.BeerStyleBulkResult{Results: []domain.BeerStyleBulkItemResult{
+		{Index: 1, Status: domain.BulkItemSucceeded, Data: &domain.BeerStyle{UUID: "uuid-lager"}},
+	}})
+
+	report := plan.Report(false, true)
+	actions := make([]string, len(report.Items))
+	for i, item := range report.Items {
+		actions[i] = item.Action
+	}
+
+	expected := []string{"unchanged", "update", "create", "conflict", "invalid", "invalid"}
+	if strings.Join(actions, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected actions %v, got %v", expected, actions)
+	}
+	if report.Creates != 1 || report.Updates != 1 || report.Unchanged != 1 || report.Conflicts != 1 || report.Invalid != 2 {
+		t.Errorf("Unexpected counts %+v", report)
+	}
+	if report.Items[2].UUID != "uuid-lager" || report.Items[0].UUID != "uuid-ipa" {
+		t.Errorf("Expected UUIDs on matched and created rows, got %+v", report.Items)
+	}
+	if failed := plan.Failed(); failed == nil || failed.Row != 5 {
+		t.Errorf("Expected row 5 to be the first failure, got %+v", failed)
+	}
+}
+
+func TestPlanBeerStyleImport_MatchesNamesIgnoringCaseAndAccents(t *testing.T) {
+	existing := []domain.BeerStyle{{UUID: "uuid-marzen", Name: "Marzen", TempMin: 4, TempMax: 7, Version: 2}}
+	rows := []domain.BeerStyleImportRow{
+		{Row: 2, Name: "Märzen", TempMin: 4, TempMax: 7},
+		{Row: 3, Name: "MARZEN", TempMin: 4, TempMax: 7},
+	}
+
+	plan := PlanBeerStyleImport(rows, existing, foldedNameKeys(ImportNameCandidates(rows, existing)))
+
+	if len(plan.Operations) != 1 {
+		t.Fatalf("Expected 1 operation, got %+v", plan.Operations)
+	}
+	update := plan.Operations[0]
+	if update.Op != domain.BulkOperationUpdate || update.UUID != "uuid-marzen" || update.Name == nil || *update.Name != "Märzen" {
+		t.Errorf("Expected Marzen to be renamed to Märzen, got %+v", update)
+	}
+
+	report := plan.Report(true, false)
+	if report.Updates != 1 || report.Conflicts != 1 {
+		t.Errorf("Expected one update and one conflict, got %+v", report)
+	}
+}
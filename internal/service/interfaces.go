@@ -1,28 +1,75 @@
 package service
 
-import "backend-test/internal/domain"
+import (
+	"backend-test/internal/domain"
+	"context"
+	"time"
+)
 
 type BeerServiceInterface interface {
-	ListAllBeerStyles() ([]domain.BeerStyle, error)
-	GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle, error)
-	CreateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error)
-	UpdateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error)
-	DeleteBeerStyle(beerUUID string) error
+	ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error)
+	ListBeerStyles(ctx context.Context, params domain.BeerStyleListParams) (domain.BeerStylePage, error)
+	GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error)
+	GetBeerStyleByUUIDIncludingDeleted(ctx context.Context, beerUUID string) (domain.BeerStyle, error)
+	CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error)
+	UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error)
+	DeleteBeerStyle(ctx context.Context, beerUUID string) error
+	RestoreBeerStyle(ctx context.Context, beerUUID string) (domain.BeerStyle, error)
+	ListBeerStyleHistory(ctx context.Context, params domain.BeerStyleHistoryParams) (domain.BeerStyleHistoryPage, error)
+	ApplyBeerStyleBulk(ctx context.Context, request domain.BeerStyleBulkRequest, rejected []error) (domain.BeerStyleBulkResult, error)
+	ExportBeerStyles(ctx context.Context, fn func([]domain.BeerStyle) error) error
+	BeerStyleNameKeys(ctx context.Context, names []string) (map[string]string, error)
+	ListBeerStylePlaylists(ctx context.Context, beerUUID string) ([]domain.BeerStylePlaylist, error)
+	CuratedPlaylists(ctx context.Context, beerUUID string) ([]domain.BeerStylePlaylist, error)
+	AddBeerStylePlaylist(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error)
+	UpdateBeerStylePlaylist(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error)
+	RemoveBeerStylePlaylist(ctx context.Context, beerUUID, playlistID string) error
+}
+
+// DeletedBeerStylePurger permanently removes soft-deleted beer styles.
+type DeletedBeerStylePurger interface {
+	PurgeDeletedBeerStyles(ctx context.Context, retention time.Duration) (int64, error)
 }
 
 type ValidationServiceInterface interface {
 	ValidateTemperatureRange(beerStyle domain.BeerStyle) error
 	ValidateTemperatureInput(temperature float64) error
-	ValidateUniqueNameForCreate(name string) error
-	ValidateUniqueNameForUpdate(name string, excludeUUID string) error
-	IsNoRowsError(err error) bool
+	ValidateWeatherQuery(query domain.WeatherQuery) error
+	ValidateListParams(params domain.BeerStyleListParams) error
 	ValidateUUID(uuidStr string) error
+	ValidateBulkOperations(ctx context.Context, operations []domain.BeerStyleBulkOperation) ([]error, error)
+	ValidateBulkOperationsAgainst(beerStyles []domain.BeerStyle, keys map[string]string, operations []domain.BeerStyleBulkOperation) []error
 }
 
 type UpdateServiceInterface interface {
 	ApplyBeerStyleUpdates(current *domain.BeerStyle, updates domain.BeerStyleUpdateRequest) bool
+	ApplyMergePatch(current domain.BeerStyle, patch []byte) (domain.BeerStyle, error)
+	ApplyJSONPatch(current domain.BeerStyle, patch []byte) (domain.BeerStyle, error)
+}
+
+// MusicProvider finds a playlist matching a free-text query, or loads one by
+// ID, in some music catalog. Implementations return domain.ErrNotFound when
+// nothing matches.
+type MusicProvider interface {
+	SearchPlaylist(ctx context.Context, query string) (*domain.PlaylistInfo, error)
+	GetPlaylist(ctx context.Context, playlistID string) (*domain.PlaylistInfo, error)
+}
+
+// WeatherProvider reads the current temperature at a city or coordinates.
+// Implementations return domain.ErrNotFound for unknown locations.
+type WeatherProvider interface {
+	CurrentWeather(ctx context.Context, query domain.WeatherQuery) (*domain.WeatherObservation, error)
+}
+
+type PlaylistCacheStatsProvider interface {
+	Stats() domain.PlaylistCacheStats
+}
+
+type MusicProviderStatusProvider interface {
+	Status() domain.MusicProviderStatus
 }
 
 type RecommendationServiceInterface interface {
-	GetRecommendationForTemperature(temperature float64) (*domain.RecommendationResponse, error)
+	GetRecommendationForTemperature(ctx context.Context, temperature float64, options domain.RecommendationOptions) (*domain.RecommendationResponse, error)
+	GetRecommendationForLocation(ctx context.Context, query domain.WeatherQuery, options domain.RecommendationOptions) (*domain.RecommendationResponse, error)
 }
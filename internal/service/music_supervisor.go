@@ -0,0 +1,276 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// MusicProviderConnector builds a ready-to-use provider. The returned close
+// function, when non-nil, is called once the provider is discarded and every
+// call still using it has returned, so it may tear the client down.
+type MusicProviderConnector func(ctx context.Context) (MusicProvider, func() error, error)
+
+type SupervisorConfig struct {
+	// MinDelay and MaxDelay bound the exponential backoff between failed
+	// connection attempts.
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// FailureThreshold is the number of consecutive upstream failures after
+	// which a connected provider is dropped and rebuilt. Zero disables it.
+	FailureThreshold int
+}
+
+// SupervisedMusicProvider connects to a music provider in the background,
+// retrying with backoff until it succeeds, so the API can boot while the
+// catalog is unreachable. Lookups fail with domain.ErrUpstreamUnavailable
+// until a client is ready, and a client that keeps failing is reconnected.
+type SupervisedMusicProvider struct {
+	name    string
+	connect MusicProviderConnector
+	config  SupervisorConfig
+	now     func() time.Time
+
+	reconnect chan struct{}
+	cancel    context.CancelFunc
+	done      chan struct{}
+
+	mu       sync.RWMutex
+	provider MusicProvider
+	closeFn  func() error
+	// inFlight counts the calls using provider; it is replaced with it.
+	inFlight *sync.WaitGroup
+	failures int
+	status   domain.MusicProviderStatus
+}
+
+func NewSupervisedMusicProvider(name string, connect MusicProviderConnector, cfg SupervisorConfig) *SupervisedMusicProvider {
+	return &SupervisedMusicProvider{
+		name:      name,
+		connect:   connect,
+		config:    cfg,
+		now:       time.Now,
+		reconnect: make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		status: domain.MusicProviderStatus{
+			Provider: name,
+			State:    domain.MusicProviderStateConnecting,
+		},
+	}
+}
+
+// Start launches the connection loop. It returns immediately; use Status to
+// follow its progress and Close to stop it.
+func (sp *SupervisedMusicProvider) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	sp.cancel = cancel
+
+	go sp.run(ctx)
+}
+
+func (sp *SupervisedMusicProvider) SearchPlaylist(ctx context.Context, query string) (*domain.PlaylistInfo, error) {
+	provider, inFlight := sp.acquire()
+	if provider == nil {
+		return nil, domain.NewUpstreamUnavailableError(nil, "%s is not connected yet", sp.name)
+	}
+	defer inFlight.Done()
+
+	playlist, err := provider.SearchPlaylist(ctx, query)
+	sp.recordResult(ctx, provider, err)
+	return playlist, err
+}
+
+func (sp *SupervisedMusicProvider) GetPlaylist(ctx context.Context, playlistID string) (*domain.PlaylistInfo, error) {
+	provider, inFlight := sp.acquire()
+	if provider == nil {
+		return nil, domain.NewUpstreamUnavailableError(nil, "%s is not connected yet", sp.name)
+	}
+	defer inFlight.Done()
+
+	playlist, err := provider.GetPlaylist(ctx, playlistID)
+	sp.recordResult(ctx, provider, err)
+	return playlist, err
+}
+
+// acquire returns the current client, if any, and counts the caller as using
+// it until it calls Done on the returned group.
+func (sp *SupervisedMusicProvider) acquire() (MusicProvider, *sync.WaitGroup) {
+	sp.mu.RLock()
+	defer sp.mu.RUnlock()
+
+	if sp.provider == nil {
+		return nil, nil
+	}
+	sp.inFlight.Add(1)
+	return sp.provider, sp.inFlight
+}
+
+func (sp *SupervisedMusicProvider) Status() domain.MusicProviderStatus {
+	sp.mu.RLock()
+	defer sp.mu.RUnlock()
+
+	return sp.status
+}
+
+// Close stops the connection loop and releases the current client.
+func (sp *SupervisedMusicProvider) Close() error {
+	if sp.cancel == nil {
+		return nil
+	}
+
+	sp.cancel()
+	<-sp.done
+
+	sp.mu.Lock()
+	closeClient := sp.discardLocked()
+	sp.mu.Unlock()
+
+	return closeClient()
+}
+
+func (sp *SupervisedMusicProvider) run(ctx context.Context) {
+	defer close(sp.done)
+
+	for {
+		delay := sp.config.MinDelay
+		for !sp.attempt(ctx) {
+			if !sp.wait(ctx, delay) {
+				return
+			}
+			delay = sp.nextDelay(delay)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-sp.reconnect:
+		}
+
+		sp.mu.Lock()
+		closeClient := sp.discardLocked()
+		sp.status.State = domain.MusicProviderStateReconnecting
+		sp.status.ConnectedAt = nil
+		select {
+		case <-sp.reconnect:
+		default:
+		}
+		sp.mu.Unlock()
+
+		if err := closeClient(); err != nil {
+			log.Printf("service=SupervisedMusicProvider provider=%s failed to close client: %v", sp.name, err)
+		}
+	}
+}
+
+func (sp *SupervisedMusicProvider) attempt(ctx context.Context) bool {
+	provider, closeFn, err := sp.connect(ctx)
+	attemptedAt := sp.now()
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	sp.status.Attempts++
+	sp.status.LastAttemptAt = &attemptedAt
+	sp.status.NextAttemptAt = nil
+
+	if err != nil {
+		sp.status.LastError = err.Error()
+		if ctx.Err() == nil {
+			log.Printf("service=SupervisedMusicProvider provider=%s attempt=%d err=%v", sp.name, sp.status.Attempts, err)
+		}
+		return false
+	}
+
+	sp.provider = provider
+	sp.closeFn = closeFn
+	sp.inFlight = &sync.WaitGroup{}
+	sp.failures = 0
+	sp.status.State = domain.MusicProviderStateReady
+	sp.status.LastError = ""
+	sp.status.ConnectedAt = &attemptedAt
+
+	log.Printf("service=SupervisedMusicProvider provider=%s connected after %d attempt(s)", sp.name, sp.status.Attempts)
+	return true
+}
+
+func (sp *SupervisedMusicProvider) wait(ctx context.Context, delay time.Duration) bool {
+	nextAttempt := sp.now().Add(delay)
+	sp.mu.Lock()
+	sp.status.NextAttemptAt = &nextAttempt
+	sp.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (sp *SupervisedMusicProvider) nextDelay(delay time.Duration) time.Duration {
+	delay *= 2
+	if delay <= 0 || (sp.config.MaxDelay > 0 && delay > sp.config.MaxDelay) {
+		delay = sp.config.MaxDelay
+	}
+	return delay
+}
+
+// recordResult counts consecutive upstream failures of the current client
+// and asks the loop to rebuild it once FailureThreshold is reached. Not found
+// results and calls abandoned by the caller do not count.
+func (sp *SupervisedMusicProvider) recordResult(ctx context.Context, provider MusicProvider, err error) {
+	if err != nil && (errors.Is(err, domain.ErrNotFound) || ctx.Err() != nil) {
+		return
+	}
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	if sp.provider != provider {
+		return
+	}
+
+	if err == nil {
+		sp.failures = 0
+		return
+	}
+
+	sp.failures++
+	sp.status.LastError = err.Error()
+	if sp.config.FailureThreshold <= 0 || sp.failures < sp.config.FailureThreshold {
+		return
+	}
+
+	select {
+	case sp.reconnect <- struct{}{}:
+		log.Printf("service=SupervisedMusicProvider provider=%s reconnecting after %d consecutive failures", sp.name, sp.failures)
+	default:
+	}
+}
+
+// discardLocked drops the current client so no new call picks it up, and
+// returns a function that waits for the calls still using it and then closes
+// it. The caller must hold sp.mu and run the returned function without it.
+func (sp *SupervisedMusicProvider) discardLocked() func() error {
+	closeFn, inFlight := sp.closeFn, sp.inFlight
+	sp.provider = nil
+	sp.closeFn = nil
+	sp.inFlight = nil
+	sp.failures = 0
+
+	return func() error {
+		if inFlight != nil {
+			inFlight.Wait()
+		}
+		if closeFn == nil {
+			return nil
+		}
+		return closeFn()
+	}
+}
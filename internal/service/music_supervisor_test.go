@@ -0,0 +1,182 @@
+package service
+
+import (
+	"backend-test/external/fake"
+	"backend-test/internal/domain"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type flakyConnector struct {
+	failures int32
+	attempts atomic.Int32
+	closed   atomic.Int32
+	provider *fake.MusicProvider
+}
+
+func (fc *flakyConnector) connect(ctx context.Context) (MusicProvider, func() error, error) {
+	if fc.attempts.Add(1) <= fc.failures {
+		return nil, nil, errors.New("spotify is unreachable")
+	}
+
+	return fc.provider, func() error {
+		fc.closed.Add(1)
+		return nil
+	}, nil
+}
+
+func waitForState(t *testing.T, sp *SupervisedMusicProvider, state string) domain.MusicProviderStatus {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if status := sp.Status(); status.State == state {
+			return status
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("Expected state %q, got %+v", state, sp.Status())
+	return domain.MusicProviderStatus{}
+}
+
+func TestSupervisedMusicProvider_UnavailableUntilConnected(t *testing.T) {
+	release := make(chan struct{})
+	connector := func(ctx context.Context) (MusicProvider, func() error, error) {
+		<-release
+		return fake.NewDemoMusicProvider(), nil, nil
+	}
+
+	supervisor := NewSupervisedMusicProvider("spotify", connector, SupervisorConfig{MinDelay: time.Millisecond})
+	supervisor.Start()
+	defer supervisor.Close()
+
+	if _, err := supervisor.SearchPlaylist(context.Background(), "IPA"); !errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Errorf("Expected upstream unavailable error before connecting, got %v", err)
+	}
+
+	if state := supervisor.Status().State; state != domain.MusicProviderStateConnecting {
+		t.Errorf("Expected state %q, got %q", domain.MusicProviderStateConnecting, state)
+	}
+
+	close(release)
+	waitForState(t, supervisor, domain.MusicProviderStateReady)
+
+	if _, err := supervisor.SearchPlaylist(context.Background(), "IPA"); err != nil {
+		t.Errorf("Expected no error once connected, got %v", err)
+	}
+}
+
+func TestSupervisedMusicProvider_RetriesFailedConnections(t *testing.T) {
+	connector := &flakyConnector{failures: 3, provider: fake.NewDemoMusicProvider()}
+
+	supervisor := NewSupervisedMusicProvider("spotify", connector.connect, SupervisorConfig{
+		MinDelay: time.Millisecond,
+		MaxDelay: 2 * time.Millisecond,
+	})
+	supervisor.Start()
+
+	status := waitForState(t, supervisor, domain.MusicProviderStateReady)
+	if status.Attempts != 4 {
+		t.Errorf("Expected 4 attempts, got %d", status.Attempts)
+	}
+	if status.LastError != "" || status.ConnectedAt == nil {
+		t.Errorf("Expected a clean connected status, got %+v", status)
+	}
+
+	if err := supervisor.Close(); err != nil {
+		t.Fatalf("Expected no error closing, got %v", err)
+	}
+	if connector.closed.Load() != 1 {
+		t.Errorf("Expected the client to be closed once, got %d", connector.closed.Load())
+	}
+}
+
+func TestSupervisedMusicProvider_ReconnectsAfterConsecutiveFailures(t *testing.T) {
+	provider := fake.NewMusicProvider()
+	provider.AddPlaylist("IPA", domain.PlaylistInfo{Name: "IPA Vibes"})
+	connector := &flakyConnector{provider: provider}
+
+	supervisor := NewSupervisedMusicProvider("spotify", connector.connect, SupervisorConfig{
+		MinDelay:         time.Millisecond,
+		FailureThreshold: 2,
+	})
+	supervisor.Start()
+	defer supervisor.Close()
+
+	waitForState(t, supervisor, domain.MusicProviderStateReady)
+
+	if _, err := supervisor.SearchPlaylist(context.Background(), "Unknown"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("Expected not found error, got %v", err)
+	}
+
+	provider.Err = errors.New("token revoked")
+	for i := 0; i < 2; i++ {
+		supervisor.SearchPlaylist(context.Background(), "IPA")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for connector.attempts.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	provider.Err = nil
+	status := waitForState(t, supervisor, domain.MusicProviderStateReady)
+
+	if connector.attempts.Load() != 2 || connector.closed.Load() != 1 {
+		t.Errorf("Expected one reconnection closing the old client, got %d attempts and %d closes", connector.attempts.Load(), connector.closed.Load())
+	}
+	if status.Attempts != 2 {
+		t.Errorf("Expected 2 attempts in status, got %d", status.Attempts)
+	}
+}
+
+func TestSupervisedMusicProvider_ClosesClientAfterInFlightCalls(t *testing.T) {
+	provider := &blockingMusicProvider{release: make(chan struct{})}
+	var closed atomic.Int32
+	connector := func(ctx context.Context) (MusicProvider, func() error, error) {
+		return provider, func() error {
+			closed.Add(1)
+			return nil
+		}, nil
+	}
+
+	supervisor := NewSupervisedMusicProvider("spotify", connector, SupervisorConfig{MinDelay: time.Millisecond})
+	supervisor.Start()
+	defer supervisor.Close()
+
+	waitForState(t, supervisor, domain.MusicProviderStateReady)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := supervisor.SearchPlaylist(context.Background(), "IPA")
+		result <- err
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for provider.calls.Load() < 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	supervisor.reconnect <- struct{}{}
+	time.Sleep(50 * time.Millisecond)
+	if closed.Load() != 0 {
+		t.Fatalf("Expected the client to stay open while a call is using it, got %d closes", closed.Load())
+	}
+
+	close(provider.release)
+	if err := <-result; err != nil {
+		t.Fatalf("Expected the in-flight call to finish, got %v", err)
+	}
+
+	deadline = time.Now().Add(2 * time.Second)
+	for closed.Load() < 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if closed.Load() != 1 {
+		t.Errorf("Expected the client to be closed once the call returned, got %d closes", closed.Load())
+	}
+}
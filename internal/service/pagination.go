@@ -0,0 +1,78 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+const (
+	DefaultBeerStyleListLimit = 50
+	MaxBeerStyleListLimit     = 100
+
+	SortByName      = "name"
+	SortByCreatedAt = "created_at"
+
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+const cursorTimeLayout = "2006-01-02T15:04:05.999999"
+
+func EncodeBeerStyleCursor(sortBy string, beerStyle domain.BeerStyle) string {
+	cursor := domain.BeerStyleCursor{SortBy: sortBy, UUID: beerStyle.UUID}
+
+	switch sortBy {
+	case SortByCreatedAt:
+		cursor.Value = beerStyle.CreatedAt.Format(cursorTimeLayout)
+	default:
+		cursor.Value = beerStyle.Name
+	}
+
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeBeerStyleCursor(encoded string) (*domain.BeerStyleCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, domain.NewValidationError("invalid cursor")
+	}
+
+	var cursor domain.BeerStyleCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.UUID == "" {
+		return nil, domain.NewValidationError("invalid cursor")
+	}
+
+	if cursor.SortBy == SortByCreatedAt {
+		if _, err := time.Parse(cursorTimeLayout, cursor.Value); err != nil {
+			return nil, domain.NewValidationError("invalid cursor")
+		}
+	}
+
+	return &cursor, nil
+}
+
+type historyCursor struct {
+	ID int64 `json:"id"`
+}
+
+func EncodeHistoryCursor(entry domain.BeerStyleAuditEntry) string {
+	raw, _ := json.Marshal(historyCursor{ID: entry.ID})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeHistoryCursor(encoded string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, domain.NewValidationError("invalid cursor")
+	}
+
+	var cursor historyCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID <= 0 {
+		return 0, domain.NewValidationError("invalid cursor")
+	}
+
+	return cursor.ID, nil
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// mergePatch applies an RFC 7396 JSON Merge Patch to target: objects are
+// merged recursively, null removes a member and anything else replaces it.
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+
+	return targetObject
+}
+
+type jsonPatchOperation struct {
+	Op    string           `json:"op"`
+	Path  *string          `json:"path"`
+	From  *string          `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+// applyJSONPatch applies an RFC 6902 JSON Patch. Operations run in order and
+// the whole patch fails if any of them does, leaving doc unusable.
+func applyJSONPatch(doc interface{}, operations []jsonPatchOperation) (interface{}, error) {
+	var err error
+	for i, operation := range operations {
+		doc, err = applyJSONPatchOperation(doc, operation)
+		if err != nil {
+			return nil, withOperationIndex(err, i)
+		}
+	}
+	return doc, nil
+}
+
+func applyJSONPatchOperation(doc interface{}, operation jsonPatchOperation) (interface{}, error) {
+	if operation.Path == nil {
+		return nil, domain.NewValidationError("'%s' operation requires a path", operation.Op)
+	}
+	path, err := parseJSONPointer(*operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "add", "replace", "test":
+		if operation.Value == nil {
+			return nil, domain.NewValidationError("'%s' operation requires a value", operation.Op)
+		}
+		var value interface{}
+		if err := json.Unmarshal(*operation.Value, &value); err != nil {
+			return nil, domain.NewValidationError("invalid value for '%s' operation", operation.Op)
+		}
+
+		switch operation.Op {
+		case "add":
+			return addAtPointer(doc, path, value)
+		case "replace":
+			if _, err := getAtPointer(doc, path); err != nil {
+				return nil, err
+			}
+			doc, _, err = removeAtPointer(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			return addAtPointer(doc, path, value)
+		default:
+			current, err := getAtPointer(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, domain.NewConflictError("test failed at path '%s'", *operation.Path)
+			}
+			return doc, nil
+		}
+
+	case "remove":
+		doc, _, err = removeAtPointer(doc, path)
+		return doc, err
+
+	case "move", "copy":
+		if operation.From == nil {
+			return nil, domain.NewValidationError("'%s' operation requires a from", operation.Op)
+		}
+		from, err := parseJSONPointer(*operation.From)
+		if err != nil {
+			return nil, err
+		}
+
+		var value interface{}
+		if operation.Op == "move" {
+			if isProperPrefix(from, path) {
+				return nil, domain.NewValidationError("cannot move '%s' into one of its children", *operation.From)
+			}
+			doc, value, err = removeAtPointer(doc, from)
+		} else {
+			value, err = getAtPointer(doc, from)
+			value = deepCopyJSON(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return addAtPointer(doc, path, value)
+
+	default:
+		return nil, domain.NewValidationError("unsupported patch operation '%s'", operation.Op)
+	}
+}
+
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, domain.NewValidationError("invalid JSON pointer '%s'", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+func getAtPointer(doc interface{}, path []string) (interface{}, error) {
+	current := doc
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, pathNotFound(path)
+			}
+			current = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			current = node[index]
+		default:
+			return nil, pathNotFound(path)
+		}
+	}
+	return current, nil
+}
+
+func addAtPointer(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := getAtPointer(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	token := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[token] = value
+		return doc, nil
+	case []interface{}:
+		index := len(node)
+		if token != "-" {
+			if index, err = arrayIndex(token, len(node)); err != nil {
+				return nil, err
+			}
+		}
+		updated := append(node[:index:index], append([]interface{}{value}, node[index:]...)...)
+		return replaceAtPointer(doc, path[:len(path)-1], updated)
+	default:
+		return nil, pathNotFound(path)
+	}
+}
+
+func removeAtPointer(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+
+	parent, err := getAtPointer(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	token := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		value, ok := node[token]
+		if !ok {
+			return nil, nil, pathNotFound(path)
+		}
+		delete(node, token)
+		return doc, value, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := node[index]
+		updated := append(node[:index:index], node[index+1:]...)
+		doc, err = replaceAtPointer(doc, path[:len(path)-1], updated)
+		return doc, value, err
+	default:
+		return nil, nil, pathNotFound(path)
+	}
+}
+
+// replaceAtPointer swaps the node at path, needed when an array grows or
+// shrinks and its parent must point at the new slice.
+func replaceAtPointer(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := getAtPointer(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	token := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[token] = value
+	case []interface{}:
+		index, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = value
+	}
+	return doc, nil
+}
+
+func arrayIndex(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, domain.NewValidationError("invalid array index '%s'", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max {
+		return 0, domain.NewValidationError("array index '%s' out of bounds", token)
+	}
+	return index, nil
+}
+
+func isProperPrefix(prefix, path []string) bool {
+	if len(prefix) >= len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func deepCopyJSON(value interface{}) interface{} {
+	raw, _ := json.Marshal(value)
+	var copied interface{}
+	_ = json.Unmarshal(raw, &copied)
+	return copied
+}
+
+func pathNotFound(path []string) error {
+	return domain.NewValidationError("path '/%s' does not exist", strings.Join(path, "/"))
+}
+
+func withOperationIndex(err error, index int) error {
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) {
+		return err
+	}
+	return &domain.Error{
+		Kind:    domainErr.Kind,
+		Message: "operation " + strconv.Itoa(index) + ": " + domainErr.Message,
+	}
+}
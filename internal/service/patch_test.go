@@ -0,0 +1,134 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUpdateService_ApplyMergePatch(t *testing.T) {
+	current := domain.BeerStyle{
+		UUID:      "test-uuid-1",
+		Name:      "Test IPA",
+		TempMin:   4,
+		TempMax:   7,
+		CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		Version:   3,
+	}
+
+	tests := []struct {
+		name     string
+		patch    string
+		expected domain.BeerStyle
+		errKind  error
+	}{
+		{"replaces given fields", `{"name": "Session IPA", "temp_max": 6.5}`, domain.BeerStyle{Name: "Session IPA", TempMin: 4, TempMax: 6.5}, nil},
+		{"empty patch keeps everything", `{}`, domain.BeerStyle{Name: "Test IPA", TempMin: 4, TempMax: 7}, nil},
+		{"explicit zero is applied", `{"temp_min": 0}`, domain.BeerStyle{Name: "Test IPA", TempMin: 0, TempMax: 7}, nil},
+		{"null removes a required field", `{"name": null}`, domain.BeerStyle{}, domain.ErrValidation},
+		{"empty name", `{"name": ""}`, domain.BeerStyle{}, domain.ErrValidation},
+		{"wrong type", `{"temp_min": "cold"}`, domain.BeerStyle{}, domain.ErrValidation},
+		{"read-only field", `{"version": 9}`, domain.BeerStyle{}, domain.ErrValidation},
+		{"unknown field", `{"color": "amber"}`, domain.BeerStyle{}, domain.ErrValidation},
+		{"not an object", `["name"]`, domain.BeerStyle{}, domain.ErrValidation},
+		{"malformed", `{"name":`, domain.BeerStyle{}, domain.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched, err := NewUpdateService().ApplyMergePatch(current, []byte(tt.patch))
+			assertPatchResult(t, current, patched, err, tt.expected, tt.errKind)
+		})
+	}
+}
+
+func TestUpdateService_ApplyJSONPatch(t *testing.T) {
+	current := domain.BeerStyle{
+		UUID:    "test-uuid-1",
+		Name:    "Test IPA",
+		TempMin: 4,
+		TempMax: 7,
+		Version: 3,
+	}
+
+	tests := []struct {
+		name     string
+		patch    string
+		expected domain.BeerStyle
+		errKind  error
+	}{
+		{"replace", `[{"op": "replace", "path": "/name", "value": "Session IPA"}]`, domain.BeerStyle{Name: "Session IPA", TempMin: 4, TempMax: 7}, nil},
+		{"test then replace", `[{"op": "test", "path": "/version", "value": 3}, {"op": "replace", "path": "/temp_min", "value": 2}]`, domain.BeerStyle{Name: "Test IPA", TempMin: 2, TempMax: 7}, nil},
+		{"failed test", `[{"op": "test", "path": "/name", "value": "Stout"}, {"op": "replace", "path": "/temp_min", "value": 2}]`, domain.BeerStyle{}, domain.ErrConflict},
+		{"copy", `[{"op": "copy", "from": "/temp_max", "path": "/temp_min"}, {"op": "replace", "path": "/temp_max", "value": 9}]`, domain.BeerStyle{Name: "Test IPA", TempMin: 7, TempMax: 9}, nil},
+		{"move leaves the source missing", `[{"op": "move", "from": "/temp_max", "path": "/temp_min"}]`, domain.BeerStyle{}, domain.ErrValidation},
+		{"remove required field", `[{"op": "remove", "path": "/name"}]`, domain.BeerStyle{}, domain.ErrValidation},
+		{"replace missing path", `[{"op": "replace", "path": "/color", "value": "amber"}]`, domain.BeerStyle{}, domain.ErrValidation},
+		{"add unknown field", `[{"op": "add", "path": "/color", "value": "amber"}]`, domain.BeerStyle{}, domain.ErrValidation},
+		{"read-only field", `[{"op": "replace", "path": "/uuid", "value": "other"}]`, domain.BeerStyle{}, domain.ErrValidation},
+		{"missing value", `[{"op": "add", "path": "/name"}]`, domain.BeerStyle{}, domain.ErrValidation},
+		{"unsupported op", `[{"op": "merge", "path": "/name"}]`, domain.BeerStyle{}, domain.ErrValidation},
+		{"invalid pointer", `[{"op": "replace", "path": "name", "value": "x"}]`, domain.BeerStyle{}, domain.ErrValidation},
+		{"not an array", `{"op": "replace"}`, domain.BeerStyle{}, domain.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched, err := NewUpdateService().ApplyJSONPatch(current, []byte(tt.patch))
+			assertPatchResult(t, current, patched, err, tt.expected, tt.errKind)
+		})
+	}
+}
+
+func TestApplyJSONPatch_Arrays(t *testing.T) {
+	doc := map[string]interface{}{"tags": []interface{}{"a", "c"}}
+	path := func(p string) *string { return &p }
+	value := func(raw string) *json.RawMessage {
+		v := json.RawMessage(raw)
+		return &v
+	}
+
+	patched, err := applyJSONPatch(doc, []jsonPatchOperation{
+		{Op: "add", Path: path("/tags/1"), Value: value(`"b"`)},
+		{Op: "add", Path: path("/tags/-"), Value: value(`"d"`)},
+		{Op: "remove", Path: path("/tags/0")},
+		{Op: "test", Path: path("/tags"), Value: value(`["b", "c", "d"]`)},
+		{Op: "add", Path: path("/a~1b"), Value: value(`1`)},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := patched.(map[string]interface{})["a/b"]; !ok {
+		t.Errorf("Expected escaped pointer to add key 'a/b', got %v", patched)
+	}
+
+	if _, err := applyJSONPatch(doc, []jsonPatchOperation{{Op: "remove", Path: path("/tags/5")}}); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected out of bounds index to fail validation, got %v", err)
+	}
+}
+
+func assertPatchResult(t *testing.T, current, patched domain.BeerStyle, err error, expected domain.BeerStyle, errKind error) {
+	t.Helper()
+
+	if errKind != nil {
+		if !errors.Is(err, errKind) {
+			t.Fatalf("Expected error kind %v, got %v", errKind, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if patched.Name != expected.Name || patched.TempMin != expected.TempMin || patched.TempMax != expected.TempMax {
+		t.Errorf("Expected %s %.1f-%.1f, got %s %.1f-%.1f",
+			expected.Name, expected.TempMin, expected.TempMax, patched.Name, patched.TempMin, patched.TempMax)
+	}
+	if patched.UUID != current.UUID || patched.Version != current.Version {
+		t.Errorf("Expected read-only fields to be preserved, got %+v", patched)
+	}
+}
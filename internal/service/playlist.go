@@ -0,0 +1,48 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"context"
+)
+
+func (bs BeerService) ListBeerStylePlaylists(ctx context.Context, beerUUID string) ([]domain.BeerStylePlaylist, error) {
+	if _, err := bs.beerRepository.GetBeerStyleByUUID(ctx, beerUUID); err != nil {
+		return nil, err
+	}
+
+	playlists, err := bs.beerRepository.ListBeerStylePlaylists(ctx, beerUUID)
+	if err != nil {
+		return nil, err
+	}
+	if playlists == nil {
+		playlists = []domain.BeerStylePlaylist{}
+	}
+	return playlists, nil
+}
+
+// CuratedPlaylists lists the playlists curated for a style without checking
+// that the style exists, for callers that already hold it.
+func (bs BeerService) CuratedPlaylists(ctx context.Context, beerUUID string) ([]domain.BeerStylePlaylist, error) {
+	return bs.beerRepository.ListBeerStylePlaylists(ctx, beerUUID)
+}
+
+func (bs BeerService) AddBeerStylePlaylist(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error) {
+	if _, err := bs.beerRepository.GetBeerStyleByUUID(ctx, playlist.BeerStyleUUID); err != nil {
+		return domain.BeerStylePlaylist{}, err
+	}
+	return bs.beerRepository.AddBeerStylePlaylist(ctx, playlist)
+}
+
+func (bs BeerService) UpdateBeerStylePlaylist(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error) {
+	if _, err := bs.beerRepository.GetBeerStyleByUUID(ctx, playlist.BeerStyleUUID); err != nil {
+		return domain.BeerStylePlaylist{}, err
+	}
+	return bs.beerRepository.UpdateBeerStylePlaylist(ctx, playlist)
+}
+
+func (bs BeerService) RemoveBeerStylePlaylist(ctx context.Context, beerUUID, playlistID string) error {
+	if _, err := bs.beerRepository.GetBeerStyleByUUID(ctx, beerUUID); err != nil {
+		return err
+	}
+	return bs.beerRepository.RemoveBeerStylePlaylist(ctx, beerUUID, playlistID)
+}
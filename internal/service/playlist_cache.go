@@ -0,0 +1,210 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	playlistRefreshTimeout = 15 * time.Second
+	// playlistStaleOnErrorMaxAge is how long past staleTTL an entry is still
+	// served when the wrapped provider fails; older entries are evicted.
+	playlistStaleOnErrorMaxAge = 24 * time.Hour
+	// playlistCacheMaxEntries caps the cache; the oldest entries go first.
+	playlistCacheMaxEntries = 1000
+)
+
+// playlistLoader asks the wrapped provider for the playlist behind a key.
+type playlistLoader func(ctx context.Context) (*domain.PlaylistInfo, error)
+
+type playlistCacheEntry struct {
+	playlist  domain.PlaylistInfo
+	fetchedAt time.Time
+}
+
+// CachedMusicProvider memoizes playlist searches per normalized query and
+// playlist lookups per ID. Entries are fresh for ttl; afterwards they are
+// served stale for up to staleTTL while a single background refresh runs, and
+// for up to playlistStaleOnErrorMaxAge more as a fallback when the wrapped
+// provider fails. Entries past that age are evicted, as are the oldest ones
+// once the cache holds maxEntries.
+type CachedMusicProvider struct {
+	next       MusicProvider
+	ttl        time.Duration
+	staleTTL   time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]playlistCacheEntry
+	stats   domain.PlaylistCacheStats
+}
+
+func NewCachedMusicProvider(next MusicProvider, ttl, staleTTL time.Duration) *CachedMusicProvider {
+	return &CachedMusicProvider{
+		next:       next,
+		ttl:        ttl,
+		staleTTL:   staleTTL,
+		maxEntries: playlistCacheMaxEntries,
+		now:        time.Now,
+		entries:    make(map[string]playlistCacheEntry),
+	}
+}
+
+func (cp *CachedMusicProvider) SearchPlaylist(ctx context.Context, query string) (*domain.PlaylistInfo, error) {
+	return cp.lookup(ctx, "search:"+normalizePlaylistQuery(query), func(ctx context.Context) (*domain.PlaylistInfo, error) {
+		return cp.next.SearchPlaylist(ctx, query)
+	})
+}
+
+func (cp *CachedMusicProvider) GetPlaylist(ctx context.Context, playlistID string) (*domain.PlaylistInfo, error) {
+	return cp.lookup(ctx, "playlist:"+playlistID, func(ctx context.Context) (*domain.PlaylistInfo, error) {
+		return cp.next.GetPlaylist(ctx, playlistID)
+	})
+}
+
+// lookup serves key from the cache, calling load on a miss, on a stale hit
+// in the background, and never more than once at a time per key.
+func (cp *CachedMusicProvider) lookup(ctx context.Context, key string, load playlistLoader) (*domain.PlaylistInfo, error) {
+	cp.mu.Lock()
+	entry, ok := cp.entries[key]
+	age := cp.now().Sub(entry.fetchedAt)
+
+	switch {
+	case ok && age < cp.ttl:
+		cp.stats.Hits++
+		cp.mu.Unlock()
+		return clonePlaylist(entry.playlist), nil
+	case ok && age < cp.ttl+cp.staleTTL:
+		cp.stats.StaleHits++
+		cp.mu.Unlock()
+		cp.refreshInBackground(ctx, key, load)
+		return clonePlaylist(entry.playlist), nil
+	case ok && age >= cp.maxAge():
+		delete(cp.entries, key)
+		cp.stats.Evictions++
+		ok = false
+	}
+
+	cp.stats.Misses++
+	cp.mu.Unlock()
+
+	playlist, err := cp.fetch(ctx, key, load)
+	if err != nil {
+		if ok && !errors.Is(err, domain.ErrNotFound) {
+			cp.mu.Lock()
+			cp.stats.StaleOnError++
+			cp.mu.Unlock()
+
+			log.Printf("service=CachedMusicProvider key=%q serving stale playlist after error: %v", key, err)
+			return clonePlaylist(entry.playlist), nil
+		}
+		return nil, err
+	}
+
+	return clonePlaylist(*playlist), nil
+}
+
+func (cp *CachedMusicProvider) Stats() domain.PlaylistCacheStats {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	stats := cp.stats
+	stats.Entries = len(cp.entries)
+	return stats
+}
+
+// maxAge is the age past which an entry is no longer served at all.
+func (cp *CachedMusicProvider) maxAge() time.Duration {
+	return cp.ttl + cp.staleTTL + playlistStaleOnErrorMaxAge
+}
+
+// evictLocked drops expired entries and then the oldest ones until the cache
+// fits in maxEntries. The caller must hold cp.mu.
+func (cp *CachedMusicProvider) evictLocked() {
+	if len(cp.entries) <= cp.maxEntries {
+		return
+	}
+
+	now := cp.now()
+	for key, entry := range cp.entries {
+		if now.Sub(entry.fetchedAt) >= cp.maxAge() {
+			delete(cp.entries, key)
+			cp.stats.Evictions++
+		}
+	}
+
+	for len(cp.entries) > cp.maxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for key, entry := range cp.entries {
+			if oldestKey == "" || entry.fetchedAt.Before(oldest) {
+				oldestKey, oldest = key, entry.fetchedAt
+			}
+		}
+		delete(cp.entries, oldestKey)
+		cp.stats.Evictions++
+	}
+}
+
+func (cp *CachedMusicProvider) refreshInBackground(ctx context.Context, key string, load playlistLoader) {
+	refreshCtx := context.WithoutCancel(ctx)
+
+	go func() {
+		if _, err := cp.fetch(refreshCtx, key, load); err != nil {
+			log.Printf("service=CachedMusicProvider key=%q background refresh failed: %v", key, err)
+		}
+	}()
+}
+
+// fetch coalesces concurrent lookups of the same key into a single call to
+// the wrapped provider and stores successful results. The call is detached
+// from the caller that started it, so one client giving up does not fail the
+// others waiting on the same key; each caller still stops at its own ctx.
+func (cp *CachedMusicProvider) fetch(ctx context.Context, key string, load playlistLoader) (*domain.PlaylistInfo, error) {
+	detached := context.WithoutCancel(ctx)
+	results := cp.group.DoChan(key, func() (interface{}, error) {
+		loadCtx, cancel := context.WithTimeout(detached, playlistRefreshTimeout)
+		defer cancel()
+
+		playlist, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		cp.mu.Lock()
+		cp.entries[key] = playlistCacheEntry{playlist: *playlist, fetchedAt: cp.now()}
+		cp.evictLocked()
+		cp.mu.Unlock()
+
+		return playlist, nil
+	})
+
+	select {
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.(*domain.PlaylistInfo), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func normalizePlaylistQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+func clonePlaylist(playlist domain.PlaylistInfo) *domain.PlaylistInfo {
+	playlist.Tracks = append([]domain.TrackInfo(nil), playlist.Tracks...)
+	return &playlist
+}
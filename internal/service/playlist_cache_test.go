@@ -0,0 +1,259 @@
+package service
+
+import (
+	"backend-test/external/fake"
+	"backend-test/internal/domain"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type blockingMusicProvider struct {
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (p *blockingMusicProvider) SearchPlaylist(ctx context.Context, query string) (*domain.PlaylistInfo, error) {
+	p.calls.Add(1)
+	select {
+	case <-p.release:
+		return &domain.PlaylistInfo{Name: query}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *blockingMusicProvider) GetPlaylist(ctx context.Context, playlistID string) (*domain.PlaylistInfo, error) {
+	p.calls.Add(1)
+	<-p.release
+	return &domain.PlaylistInfo{ID: playlistID}, nil
+}
+
+func TestCachedMusicProvider_HitsAndMisses(t *testing.T) {
+	provider := fake.NewDemoMusicProvider()
+	cache := NewCachedMusicProvider(provider, time.Minute, time.Minute)
+
+	for _, query := range []string{"IPA", "  ipa ", "Lager"} {
+		if _, err := cache.SearchPlaylist(context.Background(), query); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Entries != 2 {
+		t.Errorf("Expected 1 hit, 2 misses and 2 entries, got %+v", stats)
+	}
+
+	if provider.Calls() != 2 {
+		t.Errorf("Expected 2 provider calls, got %d", provider.Calls())
+	}
+}
+
+func TestCachedMusicProvider_ServesStaleOnError(t *testing.T) {
+	provider := fake.NewDemoMusicProvider()
+	cache := NewCachedMusicProvider(provider, time.Minute, time.Minute)
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	if _, err := cache.SearchPlaylist(context.Background(), "Stout"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	provider.Err = errors.New("spotify is down")
+	now = now.Add(3 * time.Minute)
+
+	playlist, err := cache.SearchPlaylist(context.Background(), "Stout")
+	if err != nil {
+		t.Fatalf("Expected stale playlist, got error %v", err)
+	}
+
+	if playlist.Name != "Stout Demo Playlist" {
+		t.Errorf("Expected stale playlist 'Stout Demo Playlist', got '%s'", playlist.Name)
+	}
+
+	if cache.Stats().StaleOnError != 1 {
+		t.Errorf("Expected 1 stale-on-error response, got %+v", cache.Stats())
+	}
+}
+
+func TestCachedMusicProvider_EvictsEntriesPastMaxAge(t *testing.T) {
+	provider := fake.NewDemoMusicProvider()
+	cache := NewCachedMusicProvider(provider, time.Minute, time.Minute)
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	if _, err := cache.SearchPlaylist(context.Background(), "Stout"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	provider.Err = errors.New("spotify is down")
+	now = now.Add(2*time.Minute + playlistStaleOnErrorMaxAge)
+
+	if _, err := cache.SearchPlaylist(context.Background(), "Stout"); err == nil {
+		t.Fatal("Expected an error once the entry is too old to serve, got nil")
+	}
+
+	stats := cache.Stats()
+	if stats.StaleOnError != 0 || stats.Evictions != 1 || stats.Entries != 0 {
+		t.Errorf("Expected the entry to be evicted instead of served, got %+v", stats)
+	}
+}
+
+func TestCachedMusicProvider_CapsEntries(t *testing.T) {
+	provider := fake.NewDemoMusicProvider()
+	cache := NewCachedMusicProvider(provider, time.Minute, time.Minute)
+	cache.maxEntries = 2
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	for _, query := range []string{"IPA", "Lager", "Stout"} {
+		if _, err := cache.SearchPlaylist(context.Background(), query); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		now = now.Add(time.Second)
+	}
+
+	stats := cache.Stats()
+	if stats.Entries != 2 || stats.Evictions != 1 {
+		t.Errorf("Expected 2 entries and 1 eviction, got %+v", stats)
+	}
+
+	if _, err := cache.SearchPlaylist(context.Background(), "Lager"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cache.Stats().Hits != 1 {
+		t.Errorf("Expected the newer entries to be kept, got %+v", cache.Stats())
+	}
+}
+
+func TestCachedMusicProvider_StaleWhileRevalidate(t *testing.T) {
+	provider := fake.NewDemoMusicProvider()
+	cache := NewCachedMusicProvider(provider, time.Minute, time.Hour)
+
+	var mu sync.Mutex
+	now := time.Now()
+	cache.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+
+	if _, err := cache.SearchPlaylist(context.Background(), "Porter"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	mu.Lock()
+	now = now.Add(2 * time.Minute)
+	mu.Unlock()
+
+	if _, err := cache.SearchPlaylist(context.Background(), "Porter"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cache.Stats().StaleHits != 1 {
+		t.Errorf("Expected 1 stale hit, got %+v", cache.Stats())
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for provider.Calls() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if provider.Calls() != 2 {
+		t.Errorf("Expected a background refresh, got %d provider calls", provider.Calls())
+	}
+}
+
+func TestCachedMusicProvider_CoalescesConcurrentMisses(t *testing.T) {
+	provider := &blockingMusicProvider{release: make(chan struct{})}
+	cache := NewCachedMusicProvider(provider, time.Minute, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.SearchPlaylist(context.Background(), "Saison"); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(provider.release)
+	wg.Wait()
+
+	if provider.calls.Load() != 1 {
+		t.Errorf("Expected 1 provider call, got %d", provider.calls.Load())
+	}
+}
+
+func TestCachedMusicProvider_CallerCancellationDoesNotFailOthers(t *testing.T) {
+	provider := &blockingMusicProvider{release: make(chan struct{})}
+	cache := NewCachedMusicProvider(provider, time.Minute, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := cache.SearchPlaylist(ctx, "Saison")
+		first <- err
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for provider.calls.Load() < 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the cancelled caller to stop with context.Canceled, got %v", err)
+	}
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := cache.SearchPlaylist(context.Background(), "Saison")
+		second <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	close(provider.release)
+
+	if err := <-second; err != nil {
+		t.Fatalf("Expected the second caller to get the playlist, got %v", err)
+	}
+	if provider.calls.Load() != 1 {
+		t.Errorf("Expected the load to survive the first caller and be shared, got %d provider calls", provider.calls.Load())
+	}
+}
+
+func TestCachedMusicProvider_KeepsSearchesAndPlaylistsApart(t *testing.T) {
+	provider := fake.NewDemoMusicProvider()
+	cache := NewCachedMusicProvider(provider, time.Minute, time.Minute)
+
+	searched, err := cache.SearchPlaylist(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	pinned, err := cache.GetPlaylist(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := cache.GetPlaylist(context.Background(), "abc"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if searched.ID != "" || pinned.ID != "abc" {
+		t.Errorf("Expected a searched playlist and playlist 'abc', got %+v and %+v", searched.ID, pinned.ID)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Entries != 2 {
+		t.Errorf("Expected 1 hit, 2 misses and 2 entries, got %+v", stats)
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// BeerStylePurgeJob periodically removes beer styles that were soft-deleted
+// longer than the retention window ago.
+type BeerStylePurgeJob struct {
+	purger    DeletedBeerStylePurger
+	retention time.Duration
+	interval  time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewBeerStylePurgeJob(purger DeletedBeerStylePurger, retention, interval time.Duration) *BeerStylePurgeJob {
+	return &BeerStylePurgeJob{
+		purger:    purger,
+		retention: retention,
+		interval:  interval,
+		done:      make(chan struct{}),
+	}
+}
+
+// Start runs a purge right away and then every interval until Close.
+func (j *BeerStylePurgeJob) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+
+	go j.run(ctx)
+}
+
+func (j *BeerStylePurgeJob) Close() error {
+	if j.cancel == nil {
+		return nil
+	}
+
+	j.cancel()
+	<-j.done
+	return nil
+}
+
+func (j *BeerStylePurgeJob) run(ctx context.Context) {
+	defer close(j.done)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *BeerStylePurgeJob) RunOnce(ctx context.Context) (int64, error) {
+	purged, err := j.purger.PurgeDeletedBeerStyles(ctx, j.retention)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("service=BeerStylePurgeJob retention=%s err=%v", j.retention, err)
+		}
+		return 0, err
+	}
+
+	if purged > 0 {
+		log.Printf("service=BeerStylePurgeJob retention=%s purged=%d", j.retention, purged)
+	}
+	return purged, nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingPurger struct {
+	calls     atomic.Int32
+	retention atomic.Int64
+	err       error
+}
+
+func (p *countingPurger) PurgeDeletedBeerStyles(ctx context.Context, retention time.Duration) (int64, error) {
+	p.calls.Add(1)
+	p.retention.Store(int64(retention))
+	return 2, p.err
+}
+
+func TestBeerStylePurgeJob_RunsPeriodically(t *testing.T) {
+	purger := &countingPurger{}
+	job := NewBeerStylePurgeJob(purger, 48*time.Hour, time.Millisecond)
+	job.Start()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for purger.calls.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := job.Close(); err != nil {
+		t.Fatalf("Expected no error closing, got %v", err)
+	}
+
+	if purger.calls.Load() < 3 {
+		t.Errorf("Expected at least 3 purges, got %d", purger.calls.Load())
+	}
+
+	if time.Duration(purger.retention.Load()) != 48*time.Hour {
+		t.Errorf("Expected retention 48h, got %s", time.Duration(purger.retention.Load()))
+	}
+}
+
+func TestBeerStylePurgeJob_RunOnceReturnsErrors(t *testing.T) {
+	purger := &countingPurger{err: errors.New("database unavailable")}
+	job := NewBeerStylePurgeJob(purger, time.Hour, time.Hour)
+
+	if _, err := job.RunOnce(context.Background()); err == nil {
+		t.Error("Expected error from purge")
+	}
+}
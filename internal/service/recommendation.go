@@ -1,150 +1,234 @@
 package service
 
 import (
-	"backend-test/external/spotify"
 	"backend-test/internal/domain"
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"time"
+)
+
+// MaxRecommendationCandidates caps how many ranked styles a recommendation
+// can list.
+const MaxRecommendationCandidates = 20
 
-	spotifyapi "github.com/zmb3/spotify/v2"
+var (
+	ErrBeerStyleSelection = errors.New("failed to find best beer style")
+	ErrCuratedPlaylists   = errors.New("failed to list curated playlists")
 )
 
 type RecommendationService struct {
-	beerService    BeerServiceInterface
-	spotifyService *spotify.SpotifyService
+	beerService     BeerServiceInterface
+	musicProvider   MusicProvider
+	weatherProvider WeatherProvider
+	defaultStrategy BeerStyleStrategy
+	newSeed         func() int64
 }
 
-func NewRecommendationService(beerService BeerServiceInterface, spotifyService *spotify.SpotifyService) *RecommendationService {
+// NewRecommendationService builds the service. A nil weatherProvider turns
+// off recommendations by location, and a nil defaultStrategy keeps the
+// original midpoint matching.
+func NewRecommendationService(beerService BeerServiceInterface, musicProvider MusicProvider, weatherProvider WeatherProvider, defaultStrategy BeerStyleStrategy) *RecommendationService {
+	if defaultStrategy == nil {
+		defaultStrategy = MidpointStrategy{}
+	}
+
 	return &RecommendationService{
-		beerService:    beerService,
-		spotifyService: spotifyService,
+		beerService:     beerService,
+		musicProvider:   musicProvider,
+		weatherProvider: weatherProvider,
+		defaultStrategy: defaultStrategy,
+		newSeed:         func() int64 { return time.Now().UnixNano() },
+	}
+}
+
+// FindBestBeerStyleForTemperature picks the style the strategy scores best,
+// breaking ties alphabetically.
+func (rs *RecommendationService) FindBestBeerStyleForTemperature(ctx context.Context, temperature float64, strategy BeerStyleStrategy) (*domain.BeerStyle, error) {
+	ranked, err := rs.rankBeerStylesForTemperature(ctx, temperature, strategy)
+	if err != nil {
+		return nil, err
 	}
+	return &ranked[0].style, nil
 }
 
-func (rs *RecommendationService) FindBestBeerStyleForTemperature(temperature float64) (*domain.BeerStyle, error) {
-	allBeerStyles, err := rs.beerService.ListAllBeerStyles()
+func (rs *RecommendationService) rankBeerStylesForTemperature(ctx context.Context, temperature float64, strategy BeerStyleStrategy) ([]rankedBeerStyle, error) {
+	allBeerStyles, err := rs.beerService.ListAllBeerStyles(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get beer styles: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrBeerStyleSelection, err)
 	}
 
 	if len(allBeerStyles) == 0 {
-		return nil, fmt.Errorf("no beer styles found")
-	}
-
-	type candidateStyle struct {
-		style    *domain.BeerStyle
-		distance float64
-		average  float64
-	}
-
-	var candidates []candidateStyle
-	var minDistance float64 = 999999
-
-	for i := range allBeerStyles {
-		beerStyle := &allBeerStyles[i]
-		average := (beerStyle.TempMin + beerStyle.TempMax) / 2
-		distance := abs(temperature - average)
-
-		if distance < minDistance {
-			minDistance = distance
-			candidates = []candidateStyle{{
-				style:    beerStyle,
-				distance: distance,
-				average:  average,
-			}}
-		} else if distance == minDistance {
-			candidates = append(candidates, candidateStyle{
-				style:    beerStyle,
-				distance: distance,
-				average:  average,
-			})
-		}
+		return nil, domain.NewNotFoundError("no beer styles found")
 	}
 
-	if len(candidates) == 0 {
-		return nil, fmt.Errorf("no suitable beer style found for temperature %.1f°C", temperature)
+	return rankBeerStyles(allBeerStyles, temperature, strategy), nil
+}
+
+// recommendationPlan is a validated set of recommendation options.
+type recommendationPlan struct {
+	strategy BeerStyleStrategy
+	limit    int
+	sample   trackSample
+}
+
+// resolveRecommendationOptions validates the options before anything is
+// looked up, so a bad request never spends an upstream call.
+func (rs *RecommendationService) resolveRecommendationOptions(options domain.RecommendationOptions) (recommendationPlan, error) {
+	plan := recommendationPlan{strategy: rs.defaultStrategy, limit: options.Limit}
+	if options.Strategy != "" {
+		var err error
+		if plan.strategy, err = BeerStyleStrategyByName(options.Strategy); err != nil {
+			return recommendationPlan{}, err
+		}
 	}
 
-	if len(candidates) == 1 {
-		return candidates[0].style, nil
+	// Zero is allowed and lists no candidates; the HTTP layer sends it when
+	// the limit query parameter is omitted.
+	if options.Limit < 0 || options.Limit > MaxRecommendationCandidates {
+		return recommendationPlan{}, domain.NewValidationError("limit must be between 0 and %d", MaxRecommendationCandidates)
 	}
 
-	for i := 0; i < len(candidates)-1; i++ {
-		for j := i + 1; j < len(candidates); j++ {
-			if candidates[i].style.Name > candidates[j].style.Name {
-				candidates[i], candidates[j] = candidates[j], candidates[i]
-			}
-		}
+	var err error
+	if plan.sample, err = resolveTrackSample(options, rs.newSeed); err != nil {
+		return recommendationPlan{}, err
 	}
 
-	bestMatch := candidates[0].style
-	return bestMatch, nil
+	return plan, nil
 }
-func (rs *RecommendationService) GetRecommendationForTemperature(temperature float64) (*domain.RecommendationResponse, error) {
-	beerStyle, err := rs.FindBestBeerStyleForTemperature(temperature)
+
+func (rs *RecommendationService) GetRecommendationForTemperature(ctx context.Context, temperature float64, options domain.RecommendationOptions) (*domain.RecommendationResponse, error) {
+	plan, err := rs.resolveRecommendationOptions(options)
 	if err != nil {
 		return nil, err
 	}
+	return rs.recommend(ctx, temperature, plan)
+}
 
-	var tracks []domain.TrackInfo
-	var playlistName string
+func (rs *RecommendationService) recommend(ctx context.Context, temperature float64, plan recommendationPlan) (*domain.RecommendationResponse, error) {
+	strategy, sample := plan.strategy, plan.sample
 
-	if rs.spotifyService != nil {
-		playlist, err := rs.spotifyService.SearchPlaylistByName(beerStyle.Name)
-		if err != nil {
-			log.Printf("Failed to find Spotify playlist for %s: %v", beerStyle.Name, err)
-			return nil, fmt.Errorf("no playlist found for beer style '%s'", beerStyle.Name)
-		}
+	ranked, err := rs.rankBeerStylesForTemperature(ctx, temperature, strategy)
+	if err != nil {
+		return nil, err
+	}
+	beerStyle := ranked[0].style
 
-		playlistName = playlist.Name
-		tracks = rs.convertSpotifyTracks(playlist)
+	if rs.musicProvider == nil {
+		log.Println("Music provider not available")
+		return nil, domain.NewUpstreamUnavailableError(nil, "music service is not configured")
+	}
 
-		if len(tracks) == 0 {
-			return nil, fmt.Errorf("playlist '%s' found but contains no valid tracks", playlistName)
+	curated, err := rs.beerService.CuratedPlaylists(ctx, beerStyle.UUID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCuratedPlaylists, err)
+	}
+
+	playlist, err := rs.findPlaylist(ctx, beerStyle, curated)
+	if err != nil {
+		log.Printf("Failed to find playlist for %s: %v", beerStyle.Name, err)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewNotFoundError("no playlist found for beer style '%s'", beerStyle.Name)
 		}
-	} else {
-		log.Println("Spotify service not available")
-		return nil, fmt.Errorf("spotify service unavailable")
+		return nil, domain.NewUpstreamUnavailableError(err, "music service is temporarily unavailable")
+	}
+
+	tracks := sample.Pick(playlist.Tracks)
+
+	if len(tracks) == 0 {
+		return nil, domain.NewNotFoundError("playlist '%s' found but contains no valid tracks", playlist.Name)
 	}
 
 	response := &domain.RecommendationResponse{
-		BeerStyle: beerStyle.Name,
-		Playlist: domain.PlaylistInfo{
-			Name:   playlistName,
-			Tracks: tracks,
-		},
+		BeerStyle:  beerStyle.Name,
+		Strategy:   strategy.Name(),
+		Candidates: beerStyleCandidates(ranked, plan.limit),
+		Playlist:   playlistWithTracks(*playlist, tracks),
+		Sampling:   sample.Sampling,
+		Seed:       sample.Seed,
 	}
 
 	return response, nil
 }
 
-func (rs *RecommendationService) convertSpotifyTracks(playlist *spotifyapi.FullPlaylist) []domain.TrackInfo {
-	tracks := make([]domain.TrackInfo, 0)
-	if len(playlist.Tracks.Tracks) > 0 {
-		maxTracks := len(playlist.Tracks.Tracks)
-		if maxTracks > 10 {
-			maxTracks = 10
+// GetRecommendationForLocation recommends for the temperature currently
+// observed at the location and echoes that observation in the response.
+func (rs *RecommendationService) GetRecommendationForLocation(ctx context.Context, query domain.WeatherQuery, options domain.RecommendationOptions) (*domain.RecommendationResponse, error) {
+	plan, err := rs.resolveRecommendationOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if rs.weatherProvider == nil {
+		log.Println("Weather provider not available")
+		return nil, domain.NewUpstreamUnavailableError(nil, "weather service is not configured")
+	}
+
+	query.City = strings.TrimSpace(query.City)
+	observation, err := rs.weatherProvider.CurrentWeather(ctx, query)
+	if err != nil {
+		log.Printf("service=RecommendationService func=GetRecommendationForLocation city=%q err=%v", query.City, err)
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrValidation) {
+			return nil, err
 		}
+		return nil, domain.NewUpstreamUnavailableError(err, "weather service is temporarily unavailable")
+	}
+
+	response, err := rs.recommend(ctx, observation.Temperature, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Weather = observation
+	return response, nil
+}
 
-		for i := 0; i < maxTracks; i++ {
-			track := playlist.Tracks.Tracks[i].Track
-			if track.Name != "" {
-				spotifyLink := fmt.Sprintf("https://open.spotify.com/track/%s", track.ID)
-
-				artistName := "Unknown Artist"
-				if len(track.Artists) > 0 {
-					artistName = track.Artists[0].Name
-				}
-
-				tracks = append(tracks, domain.TrackInfo{
-					Name:   track.Name,
-					Artist: artistName,
-					Link:   spotifyLink,
-				})
-			}
+// findPlaylist tries the playlists curated for the style, highest priority
+// first, and falls back to searching by the style name when none of them can
+// be loaded or has tracks.
+func (rs *RecommendationService) findPlaylist(ctx context.Context, beerStyle domain.BeerStyle, curated []domain.BeerStylePlaylist) (*domain.PlaylistInfo, error) {
+	for _, override := range curated {
+		playlist, err := rs.musicProvider.GetPlaylist(ctx, override.PlaylistID)
+		if err != nil {
+			log.Printf("service=RecommendationService func=findPlaylist playlist=%s skipping curated playlist: %v", override.PlaylistID, err)
+			continue
+		}
+		if len(playlist.Tracks) == 0 {
+			log.Printf("service=RecommendationService func=findPlaylist playlist=%s skipping curated playlist without tracks", override.PlaylistID)
+			continue
 		}
+		return playlist, nil
+	}
+
+	return rs.musicProvider.SearchPlaylist(ctx, beerStyle.Name)
+}
+
+// playlistWithTracks copies the playlist details with the sampled tracks.
+func playlistWithTracks(playlist domain.PlaylistInfo, tracks []domain.TrackInfo) domain.PlaylistInfo {
+	playlist.Tracks = tracks
+	return playlist
+}
+
+// beerStyleCandidates exposes the first limit ranked styles, best first.
+func beerStyleCandidates(ranked []rankedBeerStyle, limit int) []domain.BeerStyleCandidate {
+	if limit > len(ranked) {
+		limit = len(ranked)
+	}
+
+	candidates := make([]domain.BeerStyleCandidate, 0, limit)
+	for i, match := range ranked[:limit] {
+		candidates = append(candidates, domain.BeerStyleCandidate{
+			Rank:    i + 1,
+			UUID:    match.style.UUID,
+			Name:    match.style.Name,
+			TempMin: match.style.TempMin,
+			TempMax: match.style.TempMax,
+			Score:   match.score,
+		})
 	}
-	return tracks
+	return candidates
 }
 
 func abs(x float64) float64 {
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"backend-test/external/fake"
+	"backend-test/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type stubBeerService struct {
+	beers        []domain.BeerStyle
+	playlists    map[string][]domain.BeerStylePlaylist
+	playlistsErr error
+	// nameKeys overrides the folded keys BeerStyleNameKeys returns.
+	nameKeys map[string]string
+	err      error
+}
+
+func (s *stubBeerService) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
+	return s.beers, s.err
+}
+
+func (s *stubBeerService) ListBeerStyles(ctx context.Context, params domain.BeerStyleListParams) (domain.BeerStylePage, error) {
+	return domain.BeerStylePage{BeerStyles: s.beers}, s.err
+}
+
+func (s *stubBeerService) GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	for _, beer := range s.beers {
+		if beer.UUID == beerUUID {
+			return beer, nil
+		}
+	}
+	return domain.BeerStyle{}, domain.NewNotFoundError("beer style not found")
+}
+
+func (s *stubBeerService) GetBeerStyleByUUIDIncludingDeleted(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	return s.GetBeerStyleByUUID(ctx, beerUUID)
+}
+
+func (s *stubBeerService) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	return beerStyle, s.err
+}
+
+func (s *stubBeerService) UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	return beerStyle, s.err
+}
+
+func (s *stubBeerService) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
+	return s.err
+}
+
+func (s *stubBeerService) ListBeerStyleHistory(ctx context.Context, params domain.BeerStyleHistoryParams) (domain.BeerStyleHistoryPage, error) {
+	return domain.BeerStyleHistoryPage{}, s.err
+}
+
+func (s *stubBeerService) RestoreBeerStyle(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	return s.GetBeerStyleByUUID(ctx, beerUUID)
+}
+
+func (s *stubBeerService) ExportBeerStyles(ctx context.Context, fn func([]domain.BeerStyle) error) error {
+	if s.err != nil {
+		return s.err
+	}
+	return fn(s.beers)
+}
+
+func (s *stubBeerService) ApplyBeerStyleBulk(ctx context.Context, request domain.BeerStyleBulkRequest, rejected []error) (domain.BeerStyleBulkResult, error) {
+	return domain.BeerStyleBulkResult{}, s.err
+}
+
+func (s *stubBeerService) BeerStyleNameKeys(ctx context.Context, names []string) (map[string]string, error) {
+	keys := foldedNameKeys(names)
+	for name, key := range s.nameKeys {
+		keys[name] = key
+	}
+	return keys, s.err
+}
+
+// foldedNameKeys stands in for the database's beer_style_name_key.
+func foldedNameKeys(names []string) map[string]string {
+	keys := make(map[string]string, len(names))
+	for _, name := range names {
+		keys[name] = domain.FoldBeerStyleName(name)
+	}
+	return keys
+}
+
+func (s *stubBeerService) ListBeerStylePlaylists(ctx context.Context, beerUUID string) ([]domain.BeerStylePlaylist, error) {
+	return s.playlists[beerUUID], s.err
+}
+
+func (s *stubBeerService) CuratedPlaylists(ctx context.Context, beerUUID string) ([]domain.BeerStylePlaylist, error) {
+	return s.playlists[beerUUID], s.playlistsErr
+}
+
+func (s *stubBeerService) AddBeerStylePlaylist(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error) {
+	return playlist, s.err
+}
+
+func (s *stubBeerService) UpdateBeerStylePlaylist(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error) {
+	return playlist, s.err
+}
+
+func (s *stubBeerService) RemoveBeerStylePlaylist(ctx context.Context, beerUUID, playlistID string) error {
+	return s.err
+}
+
+func seedBeerStyles() []domain.BeerStyle {
+	return []domain.BeerStyle{
+		{UUID: "1", Name: "IPA", TempMin: 7.0, TempMax: 10.0},
+		{UUID: "2", Name: "Lager", TempMin: 3.0, TempMax: 6.0},
+		{UUID: "3", Name: "Stout", TempMin: 10.0, TempMax: 13.0},
+	}
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_UsesProvider(t *testing.T) {
+	provider := fake.NewMusicProvider()
+
+	tracks := make([]domain.TrackInfo, 0, 15)
+	for i := 0; i < 15; i++ {
+		tracks = append(tracks, domain.TrackInfo{Name: fmt.Sprintf("Track %d", i), Artist: "Artist", Link: "link"})
+	}
+	provider.AddPlaylist("lager", domain.PlaylistInfo{Name: "Lager Vibes", Tracks: tracks})
+
+	rs := NewRecommendationService(&stubBeerService{beers: seedBeerStyles()}, provider, nil, nil)
+
+	response, err := rs.GetRecommendationForTemperature(context.Background(), 4.5, domain.RecommendationOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.BeerStyle != "Lager" {
+		t.Errorf("Expected beer style 'Lager', got '%s'", response.BeerStyle)
+	}
+
+	if response.Playlist.Name != "Lager Vibes" {
+		t.Errorf("Expected playlist 'Lager Vibes', got '%s'", response.Playlist.Name)
+	}
+
+	if len(response.Playlist.Tracks) != DefaultPlaylistTracks {
+		t.Errorf("Expected %d tracks, got %d", DefaultPlaylistTracks, len(response.Playlist.Tracks))
+	}
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_CuratedPlaylists(t *testing.T) {
+	provider := fake.NewMusicProvider()
+	provider.AddPlaylist("lager", domain.PlaylistInfo{ID: "searched", Name: "Lager Vibes", Tracks: []domain.TrackInfo{{Name: "Track"}}})
+	provider.AddPlaylist("pinned", domain.PlaylistInfo{ID: "pinned", Name: "Curated Lager", Tracks: []domain.TrackInfo{{Name: "Track"}}})
+	provider.AddPlaylist("empty", domain.PlaylistInfo{ID: "empty", Name: "Empty Lager"})
+
+	tests := []struct {
+		name             string
+		playlists        []domain.BeerStylePlaylist
+		expectedPlaylist string
+	}{
+		{"no curated playlists", nil, "Lager Vibes"},
+		{"curated playlist wins", []domain.BeerStylePlaylist{{PlaylistID: "pinned"}}, "Curated Lager"},
+		{"skips missing and empty playlists", []domain.BeerStylePlaylist{{PlaylistID: "gone", Priority: 9}, {PlaylistID: "empty", Priority: 5}, {PlaylistID: "pinned"}}, "Curated Lager"},
+		{"falls back to search", []domain.BeerStylePlaylist{{PlaylistID: "gone"}, {PlaylistID: "empty"}}, "Lager Vibes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beerService := &stubBeerService{beers: seedBeerStyles(), playlists: map[string][]domain.BeerStylePlaylist{"2": tt.playlists}}
+			rs := NewRecommendationService(beerService, provider, nil, nil)
+
+			response, err := rs.GetRecommendationForTemperature(context.Background(), 4.5, domain.RecommendationOptions{})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if response.Playlist.Name != tt.expectedPlaylist {
+				t.Errorf("Expected playlist '%s', got '%s'", tt.expectedPlaylist, response.Playlist.Name)
+			}
+		})
+	}
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_CuratedPlaylistsError(t *testing.T) {
+	provider := fake.NewMusicProvider()
+	provider.AddPlaylist("lager", domain.PlaylistInfo{Name: "Lager Vibes", Tracks: []domain.TrackInfo{{Name: "Track"}}})
+
+	beerService := &stubBeerService{beers: seedBeerStyles(), playlistsErr: errors.New("database unavailable")}
+	rs := NewRecommendationService(beerService, provider, nil, nil)
+
+	_, err := rs.GetRecommendationForTemperature(context.Background(), 4.5, domain.RecommendationOptions{})
+	if !errors.Is(err, ErrCuratedPlaylists) {
+		t.Fatalf("Expected ErrCuratedPlaylists, got %v", err)
+	}
+	if errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Errorf("Expected a database failure not to be reported as upstream unavailable, got %v", err)
+	}
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_ProviderErrors(t *testing.T) {
+	tests := []struct {
+		name            string
+		provider        MusicProvider
+		expected        error
+		expectedMessage string
+	}{
+		{"no provider", nil, domain.ErrUpstreamUnavailable, "music service is not configured"},
+		{"playlist not found", fake.NewMusicProvider(), domain.ErrNotFound, ""},
+		{"provider failure", &fake.MusicProvider{Err: errors.New("connection reset")}, domain.ErrUpstreamUnavailable, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := NewRecommendationService(&stubBeerService{beers: seedBeerStyles()}, tt.provider, nil, nil)
+
+			_, err := rs.GetRecommendationForTemperature(context.Background(), 8.0, domain.RecommendationOptions{})
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected error %v, got %v", tt.expected, err)
+			}
+			if tt.expectedMessage != "" && domain.ErrorMessage(err, "") != tt.expectedMessage {
+				t.Errorf("Expected message '%s', got '%s'", tt.expectedMessage, domain.ErrorMessage(err, ""))
+			}
+		})
+	}
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_BeerStyleFailure(t *testing.T) {
+	rs := NewRecommendationService(&stubBeerService{err: errors.New("db down")}, fake.NewDemoMusicProvider(), nil, nil)
+
+	_, err := rs.GetRecommendationForTemperature(context.Background(), 8.0, domain.RecommendationOptions{})
+	if !errors.Is(err, ErrBeerStyleSelection) {
+		t.Errorf("Expected ErrBeerStyleSelection, got %v", err)
+	}
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_Strategy(t *testing.T) {
+	beers := []domain.BeerStyle{
+		{UUID: "1", Name: "Lager", TempMin: 2.0, TempMax: 5.0},
+		{UUID: "2", Name: "Saison", TempMin: 6.0, TempMax: 16.0},
+	}
+	provider := fake.NewMusicProvider()
+	provider.AddPlaylist("lager", domain.PlaylistInfo{Name: "Lager Vibes", Tracks: []domain.TrackInfo{{Name: "Track"}}})
+	provider.AddPlaylist("saison", domain.PlaylistInfo{Name: "Saison Vibes", Tracks: []domain.TrackInfo{{Name: "Track"}}})
+
+	tests := []struct {
+		name            string
+		defaultStrategy BeerStyleStrategy
+		requested       string
+		expectedStyle   string
+		expectedErr     error
+	}{
+		{"service default", nil, "", "Lager", nil},
+		{"configured default", ContainmentStrategy{}, "", "Saison", nil},
+		{"per request", nil, "edge", "Saison", nil},
+		{"request overrides config", ContainmentStrategy{}, "midpoint", "Lager", nil},
+		{"unknown strategy", nil, "closest", "", domain.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := NewRecommendationService(&stubBeerService{beers: beers}, provider, nil, tt.defaultStrategy)
+
+			response, err := rs.GetRecommendationForTemperature(context.Background(), 6.0, domain.RecommendationOptions{Strategy: tt.requested})
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if response.BeerStyle != tt.expectedStyle {
+				t.Errorf("Expected beer style '%s', got '%s'", tt.expectedStyle, response.BeerStyle)
+			}
+		})
+	}
+}
+
+func TestRecommendationService_GetRecommendationForTemperature_Candidates(t *testing.T) {
+	provider := fake.NewMusicProvider()
+	provider.AddPlaylist("ipa", domain.PlaylistInfo{Name: "IPA Vibes", Tracks: []domain.TrackInfo{{Name: "Track"}}})
+	rs := NewRecommendationService(&stubBeerService{beers: seedBeerStyles()}, provider, nil, nil)
+
+	response, err := rs.GetRecommendationForTemperature(context.Background(), 8.0, domain.RecommendationOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []domain.BeerStyleCandidate{
+		{Rank: 1, UUID: "1", Name: "IPA", TempMin: 7.0, TempMax: 10.0, Score: 0.5},
+		{Rank: 2, UUID: "2", Name: "Lager", TempMin: 3.0, TempMax: 6.0, Score: 3.5},
+	}
+	if len(response.Candidates) != len(expected) {
+		t.Fatalf("Expected %d candidates, got %+v", len(expected), response.Candidates)
+	}
+	for i := range expected {
+		if response.Candidates[i] != expected[i] {
+			t.Errorf("Expected candidate %+v, got %+v", expected[i], response.Candidates[i])
+		}
+	}
+
+	response, err = rs.GetRecommendationForTemperature(context.Background(), 8.0, domain.RecommendationOptions{Limit: 10})
+	if err != nil || len(response.Candidates) != 3 {
+		t.Errorf("Expected every style when limit exceeds the catalog, got %+v, %v", response, err)
+	}
+
+	response, err = rs.GetRecommendationForTemperature(context.Background(), 8.0, domain.RecommendationOptions{})
+	if err != nil || len(response.Candidates) != 0 {
+		t.Errorf("Expected no candidates without a limit, got %+v, %v", response, err)
+	}
+
+	if _, err := rs.GetRecommendationForTemperature(context.Background(), 8.0, domain.RecommendationOptions{Limit: MaxRecommendationCandidates + 1}); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+
+	expectedMessage := fmt.Sprintf("limit must be between 0 and %d", MaxRecommendationCandidates)
+	if _, err := rs.GetRecommendationForTemperature(context.Background(), 8.0, domain.RecommendationOptions{Limit: -1}); err == nil || err.Error() != expectedMessage {
+		t.Errorf("Expected '%s', got %v", expectedMessage, err)
+	}
+}
+
+func TestRecommendationService_GetRecommendationForLocation(t *testing.T) {
+	observedAt := time.Date(2025, 7, 1, 15, 0, 0, 0, time.UTC)
+	weather := fake.NewWeatherProvider()
+	weather.AddObservation(domain.WeatherObservation{
+		Temperature: 4.5,
+		Location:    domain.WeatherLocation{Name: "Curitiba", Country: "BR", Lat: -25.43, Lon: -49.27},
+		ObservedAt:  observedAt,
+	})
+
+	lat, lon := -25.43, -49.27
+	tests := []struct {
+		name     string
+		weather  WeatherProvider
+		query    domain.WeatherQuery
+		expected error
+	}{
+		{"by city", weather, domain.WeatherQuery{City: "curitiba"}, nil},
+		{"by coordinates", weather, domain.WeatherQuery{Lat: &lat, Lon: &lon}, nil},
+		{"unknown city", weather, domain.WeatherQuery{City: "Atlantis"}, domain.ErrNotFound},
+		{"weather failure", &fake.WeatherProvider{Err: errors.New("connection reset")}, domain.WeatherQuery{City: "Curitiba"}, domain.ErrUpstreamUnavailable},
+		{"no weather provider", nil, domain.WeatherQuery{City: "Curitiba"}, domain.ErrUpstreamUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := NewRecommendationService(&stubBeerService{beers: seedBeerStyles()}, fake.NewDemoMusicProvider(), tt.weather, nil)
+
+			response, err := rs.GetRecommendationForLocation(context.Background(), tt.query, domain.RecommendationOptions{})
+			if tt.expected != nil {
+				if !errors.Is(err, tt.expected) {
+					t.Errorf("Expected error %v, got %v", tt.expected, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if response.BeerStyle != "Lager" {
+				t.Errorf("Expected beer style 'Lager', got '%s'", response.BeerStyle)
+			}
+			if response.Weather == nil || response.Weather.Temperature != 4.5 || response.Weather.Location.Name != "Curitiba" || !response.Weather.ObservedAt.Equal(observedAt) {
+				t.Errorf("Expected the Curitiba observation, got %+v", response.Weather)
+			}
+		})
+	}
+}
+
+func TestRecommendationService_GetRecommendationForLocation_ValidatesBeforeWeather(t *testing.T) {
+	weather := fake.NewDemoWeatherProvider()
+	rs := NewRecommendationService(&stubBeerService{beers: seedBeerStyles()}, fake.NewDemoMusicProvider(), weather, nil)
+
+	invalid := []domain.RecommendationOptions{
+		{Strategy: "closest"},
+		{Limit: MaxRecommendationCandidates + 1},
+		{Sampling: "shuffled"},
+	}
+	for _, options := range invalid {
+		if _, err := rs.GetRecommendationForLocation(context.Background(), domain.WeatherQuery{City: "Curitiba"}, options); !errors.Is(err, domain.ErrValidation) {
+			t.Errorf("Expected validation error for %+v, got %v", options, err)
+		}
+	}
+	if weather.Calls() != 0 {
+		t.Errorf("Expected no weather lookups for invalid options, got %d", weather.Calls())
+	}
+
+	response, err := rs.GetRecommendationForLocation(context.Background(), domain.WeatherQuery{City: "  Curitiba "}, domain.RecommendationOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Weather.Location.Name != "Curitiba" {
+		t.Errorf("Expected the trimmed city to be looked up, got %q", response.Weather.Location.Name)
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"math/rand"
+)
+
+const (
+	DefaultPlaylistTracks = 10
+	MaxPlaylistTracks     = 50
+)
+
+// trackSample is how the tracks of a recommendation are picked: Sampling is
+// always set, and Seed is set for the random modes.
+type trackSample struct {
+	Count    int
+	Sampling string
+	Seed     *int64
+}
+
+// resolveTrackSample validates the track options. A seed without a sampling
+// mode means seeded sampling; random sampling draws a fresh seed so the
+// response can still be reproduced.
+func resolveTrackSample(options domain.RecommendationOptions, newSeed func() int64) (trackSample, error) {
+	sample := trackSample{Count: options.Tracks, Sampling: options.Sampling, Seed: options.Seed}
+
+	if sample.Count == 0 {
+		sample.Count = DefaultPlaylistTracks
+	}
+	if sample.Count < 1 || sample.Count > MaxPlaylistTracks {
+		return trackSample{}, domain.NewValidationError("tracks must be between 1 and %d", MaxPlaylistTracks)
+	}
+
+	if sample.Sampling == "" {
+		sample.Sampling = domain.TrackSamplingFirst
+		if sample.Seed != nil {
+			sample.Sampling = domain.TrackSamplingSeeded
+		}
+	}
+
+	switch sample.Sampling {
+	case domain.TrackSamplingFirst:
+		if sample.Seed != nil {
+			return trackSample{}, domain.NewValidationError("seed is only allowed with '%s' sampling", domain.TrackSamplingSeeded)
+		}
+	case domain.TrackSamplingRandom:
+		if sample.Seed != nil {
+			return trackSample{}, domain.NewValidationError("seed is only allowed with '%s' sampling", domain.TrackSamplingSeeded)
+		}
+		seed := newSeed()
+		sample.Seed = &seed
+	case domain.TrackSamplingSeeded:
+		if sample.Seed == nil {
+			return trackSample{}, domain.NewValidationError("seed is required for '%s' sampling", domain.TrackSamplingSeeded)
+		}
+	default:
+		return trackSample{}, domain.NewValidationError("sampling must be one of '%s', '%s' or '%s'",
+			domain.TrackSamplingFirst, domain.TrackSamplingRandom, domain.TrackSamplingSeeded)
+	}
+
+	return sample, nil
+}
+
+// Pick returns up to Count tracks: the first ones in playlist order, or a
+// shuffled sample that is always the same for the same seed and playlist.
+func (s trackSample) Pick(tracks []domain.TrackInfo) []domain.TrackInfo {
+	count := min(s.Count, len(tracks))
+	if s.Seed == nil {
+		return tracks[:count]
+	}
+
+	rng := rand.New(rand.NewSource(*s.Seed))
+	picked := make([]domain.TrackInfo, 0, count)
+	for _, i := range rng.Perm(len(tracks))[:count] {
+		picked = append(picked, tracks[i])
+	}
+	return picked
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestResolveTrackSample(t *testing.T) {
+	seed := int64(42)
+	newSeed := func() int64 { return 7 }
+
+	tests := []struct {
+		name             string
+		options          domain.RecommendationOptions
+		expectedCount    int
+		expectedSampling string
+		expectedSeed     *int64
+		expectedErr      error
+	}{
+		{"defaults", domain.RecommendationOptions{}, DefaultPlaylistTracks, domain.TrackSamplingFirst, nil, nil},
+		{"random draws a seed", domain.RecommendationOptions{Tracks: 3, Sampling: "random"}, 3, domain.TrackSamplingRandom, ptrInt64(7), nil},
+		{"seed implies seeded", domain.RecommendationOptions{Seed: &seed}, DefaultPlaylistTracks, domain.TrackSamplingSeeded, &seed, nil},
+		{"seeded without seed", domain.RecommendationOptions{Sampling: "seeded"}, 0, "", nil, domain.ErrValidation},
+		{"seed with first", domain.RecommendationOptions{Sampling: "first", Seed: &seed}, 0, "", nil, domain.ErrValidation},
+		{"unknown sampling", domain.RecommendationOptions{Sampling: "shuffle"}, 0, "", nil, domain.ErrValidation},
+		{"too many tracks", domain.RecommendationOptions{Tracks: MaxPlaylistTracks + 1}, 0, "", nil, domain.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sample, err := resolveTrackSample(tt.options, newSeed)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if sample.Count != tt.expectedCount || sample.Sampling != tt.expectedSampling || !reflect.DeepEqual(sample.Seed, tt.expectedSeed) {
+				t.Errorf("Expected %d/%s/%v, got %+v", tt.expectedCount, tt.expectedSampling, tt.expectedSeed, sample)
+			}
+		})
+	}
+}
+
+func TestTrackSample_Pick(t *testing.T) {
+	tracks := make([]domain.TrackInfo, 0, 30)
+	for i := 0; i < 30; i++ {
+		tracks = append(tracks, domain.TrackInfo{Name: fmt.Sprintf("Track %d", i)})
+	}
+
+	first := trackSample{Count: 5, Sampling: domain.TrackSamplingFirst}.Pick(tracks)
+	if !reflect.DeepEqual(first, tracks[:5]) {
+		t.Errorf("Expected the first 5 tracks, got %v", first)
+	}
+
+	seeded := trackSample{Count: 5, Sampling: domain.TrackSamplingSeeded, Seed: ptrInt64(42)}
+	sample := seeded.Pick(tracks)
+	if len(sample) != 5 || !reflect.DeepEqual(sample, seeded.Pick(tracks)) {
+		t.Errorf("Expected the same 5 tracks for the same seed, got %v", sample)
+	}
+	if reflect.DeepEqual(sample, trackSample{Count: 5, Seed: ptrInt64(43)}.Pick(tracks)) {
+		t.Errorf("Expected a different sample for a different seed")
+	}
+
+	seen := make(map[string]bool)
+	for _, track := range sample {
+		if seen[track.Name] {
+			t.Errorf("Expected no repeated tracks, got %v", sample)
+		}
+		seen[track.Name] = true
+	}
+
+	if all := (trackSample{Count: 50, Seed: ptrInt64(1)}).Pick(tracks[:3]); len(all) != 3 {
+		t.Errorf("Expected every track when the playlist is short, got %d", len(all))
+	}
+}
+
+func ptrInt64(value int64) *int64 {
+	return &value
+}
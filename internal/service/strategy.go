@@ -0,0 +1,143 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"sort"
+	"strings"
+)
+
+const (
+	StrategyMidpoint    = "midpoint"
+	StrategyContainment = "containment"
+	StrategyEdge        = "edge"
+	StrategyWeighted    = "weighted"
+)
+
+// BeerStyleStrategy rates how well a beer style suits a temperature. Lower
+// scores are better; ties are broken by name.
+type BeerStyleStrategy interface {
+	Name() string
+	Score(style domain.BeerStyle, temperature float64) float64
+}
+
+// MidpointStrategy prefers the style whose range midpoint is closest to the
+// temperature, regardless of whether the range contains it.
+type MidpointStrategy struct{}
+
+func (MidpointStrategy) Name() string { return StrategyMidpoint }
+
+func (MidpointStrategy) Score(style domain.BeerStyle, temperature float64) float64 {
+	return abs(temperature - midpoint(style))
+}
+
+// ContainmentStrategy always prefers styles whose range contains the
+// temperature. Among those, the one where it sits most centrally relative to
+// the range width wins; otherwise the style with the nearest edge wins.
+type ContainmentStrategy struct{}
+
+func (ContainmentStrategy) Name() string { return StrategyContainment }
+
+func (ContainmentStrategy) Score(style domain.BeerStyle, temperature float64) float64 {
+	if outside := outsideDistance(style, temperature); outside > 0 {
+		return 1 + outside
+	}
+
+	halfWidth := (style.TempMax - style.TempMin) / 2
+	if halfWidth <= 0 {
+		return 0
+	}
+	return abs(temperature-midpoint(style)) / halfWidth
+}
+
+// EdgeStrategy scores by signed distance to the nearest range edge: inside
+// the range, a larger margin is better; outside, a closer edge is better.
+type EdgeStrategy struct{}
+
+func (EdgeStrategy) Name() string { return StrategyEdge }
+
+func (EdgeStrategy) Score(style domain.BeerStyle, temperature float64) float64 {
+	if outside := outsideDistance(style, temperature); outside > 0 {
+		return outside
+	}
+	return -min(temperature-style.TempMin, style.TempMax-temperature)
+}
+
+// WeightedStrategy blends the distance to the midpoint, the distance outside
+// the range and the range width, so narrow ranges that contain the
+// temperature beat broad catch-all styles.
+type WeightedStrategy struct {
+	Midpoint float64
+	Outside  float64
+	Width    float64
+}
+
+func NewWeightedStrategy() WeightedStrategy {
+	return WeightedStrategy{Midpoint: 1, Outside: 3, Width: 0.25}
+}
+
+func (WeightedStrategy) Name() string { return StrategyWeighted }
+
+func (s WeightedStrategy) Score(style domain.BeerStyle, temperature float64) float64 {
+	return s.Midpoint*abs(temperature-midpoint(style)) +
+		s.Outside*outsideDistance(style, temperature) +
+		s.Width*(style.TempMax-style.TempMin)
+}
+
+// BeerStyleStrategies lists the available strategy names.
+func BeerStyleStrategies() []string {
+	return []string{StrategyMidpoint, StrategyContainment, StrategyEdge, StrategyWeighted}
+}
+
+// BeerStyleStrategyByName resolves a strategy name, case-insensitively.
+func BeerStyleStrategyByName(name string) (BeerStyleStrategy, error) {
+	switch strings.ToLower(name) {
+	case StrategyMidpoint:
+		return MidpointStrategy{}, nil
+	case StrategyContainment:
+		return ContainmentStrategy{}, nil
+	case StrategyEdge:
+		return EdgeStrategy{}, nil
+	case StrategyWeighted:
+		return NewWeightedStrategy(), nil
+	default:
+		return nil, domain.NewValidationError("strategy must be one of: %s", strings.Join(BeerStyleStrategies(), ", "))
+	}
+}
+
+type rankedBeerStyle struct {
+	style domain.BeerStyle
+	score float64
+}
+
+// rankBeerStyles orders styles from best to worst match.
+func rankBeerStyles(styles []domain.BeerStyle, temperature float64, strategy BeerStyleStrategy) []rankedBeerStyle {
+	ranked := make([]rankedBeerStyle, len(styles))
+	for i, style := range styles {
+		ranked[i] = rankedBeerStyle{style: style, score: strategy.Score(style, temperature)}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score < ranked[j].score
+		}
+		return ranked[i].style.Name < ranked[j].style.Name
+	})
+	return ranked
+}
+
+func midpoint(style domain.BeerStyle) float64 {
+	return (style.TempMin + style.TempMax) / 2
+}
+
+// outsideDistance is how far the temperature falls outside the range, or 0
+// when the range contains it.
+func outsideDistance(style domain.BeerStyle, temperature float64) float64 {
+	switch {
+	case temperature < style.TempMin:
+		return style.TempMin - temperature
+	case temperature > style.TempMax:
+		return temperature - style.TempMax
+	default:
+		return 0
+	}
+}
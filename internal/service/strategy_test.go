@@ -0,0 +1,100 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"errors"
+	"testing"
+)
+
+func TestBeerStyleStrategies_Rank(t *testing.T) {
+	tests := []struct {
+		name        string
+		styles      []domain.BeerStyle
+		temperature float64
+		expected    map[string]string
+	}{
+		{
+			"containing range against closer midpoint",
+			[]domain.BeerStyle{
+				{Name: "Lager", TempMin: 2, TempMax: 5},
+				{Name: "Saison", TempMin: 6, TempMax: 16},
+			},
+			6,
+			map[string]string{
+				StrategyMidpoint:    "Lager",
+				StrategyContainment: "Saison",
+				StrategyEdge:        "Saison",
+				StrategyWeighted:    "Lager",
+			},
+		},
+		{
+			"narrow range inside a broad one",
+			[]domain.BeerStyle{
+				{Name: "Amber", TempMin: 2, TempMax: 16},
+				{Name: "IPA", TempMin: 7, TempMax: 10},
+			},
+			8,
+			map[string]string{
+				StrategyMidpoint:    "IPA",
+				StrategyContainment: "Amber",
+				StrategyEdge:        "Amber",
+				StrategyWeighted:    "IPA",
+			},
+		},
+		{
+			"nothing contains the temperature",
+			[]domain.BeerStyle{
+				{Name: "Stout", TempMin: 10, TempMax: 13},
+				{Name: "Lager", TempMin: 3, TempMax: 6},
+			},
+			7,
+			map[string]string{
+				StrategyMidpoint:    "Lager",
+				StrategyContainment: "Lager",
+				StrategyEdge:        "Lager",
+				StrategyWeighted:    "Lager",
+			},
+		},
+		{
+			"ties broken by name",
+			[]domain.BeerStyle{
+				{Name: "Bock", TempMin: 4, TempMax: 8},
+				{Name: "Altbier", TempMin: 4, TempMax: 8},
+			},
+			6,
+			map[string]string{
+				StrategyMidpoint:    "Altbier",
+				StrategyContainment: "Altbier",
+				StrategyEdge:        "Altbier",
+				StrategyWeighted:    "Altbier",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for strategyName, expected := range tt.expected {
+			t.Run(tt.name+"/"+strategyName, func(t *testing.T) {
+				strategy, err := BeerStyleStrategyByName(strategyName)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+
+				ranked := rankBeerStyles(tt.styles, tt.temperature, strategy)
+				if ranked[0].style.Name != expected {
+					t.Errorf("Expected %s, got %s", expected, ranked[0].style.Name)
+				}
+			})
+		}
+	}
+}
+
+func TestBeerStyleStrategyByName(t *testing.T) {
+	strategy, err := BeerStyleStrategyByName("Containment")
+	if err != nil || strategy.Name() != StrategyContainment {
+		t.Errorf("Expected the containment strategy, got %v, %v", strategy, err)
+	}
+
+	if _, err := BeerStyleStrategyByName("closest"); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
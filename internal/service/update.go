@@ -2,8 +2,16 @@ package service
 
 import (
 	"backend-test/internal/domain"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
 )
 
+// beerStyleReadOnlyFields are part of the patched document but owned by the
+// server; a patch may test them but not change them.
+var beerStyleReadOnlyFields = []string{"uuid", "created_at", "updated_at", "deleted_at", "version"}
+
 type UpdateService struct{}
 
 func NewUpdateService() *UpdateService {
@@ -46,3 +54,109 @@ func (us *UpdateService) GetChangedFields(original domain.BeerStyle, updates dom
 
 	return changedFields
 }
+
+// ApplyMergePatch applies an RFC 7396 JSON Merge Patch to current and returns
+// the resulting beer style. Unlike ApplyBeerStyleUpdates, explicit values are
+// taken as given, so null or "" for a required field is rejected.
+func (us *UpdateService) ApplyMergePatch(current domain.BeerStyle, patch []byte) (domain.BeerStyle, error) {
+	var patchDoc interface{}
+	if err := decodePatchDocument(patch, &patchDoc); err != nil {
+		return current, err
+	}
+	if _, ok := patchDoc.(map[string]interface{}); !ok {
+		return current, domain.NewValidationError("merge patch must be a JSON object")
+	}
+
+	original, document, err := beerStyleDocuments(current)
+	if err != nil {
+		return current, err
+	}
+
+	return beerStyleFromPatchedDocument(current, original, mergePatch(document, patchDoc))
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch to current and returns the
+// resulting beer style. A failed test operation is reported as a conflict.
+func (us *UpdateService) ApplyJSONPatch(current domain.BeerStyle, patch []byte) (domain.BeerStyle, error) {
+	var operations []jsonPatchOperation
+	if err := decodePatchDocument(patch, &operations); err != nil {
+		return current, err
+	}
+
+	original, document, err := beerStyleDocuments(current)
+	if err != nil {
+		return current, err
+	}
+
+	patched, err := applyJSONPatch(document, operations)
+	if err != nil {
+		return current, err
+	}
+
+	return beerStyleFromPatchedDocument(current, original, patched)
+}
+
+func decodePatchDocument(patch []byte, target interface{}) error {
+	if len(bytes.TrimSpace(patch)) == 0 {
+		return domain.NewValidationError("patch document is required")
+	}
+	if err := json.Unmarshal(patch, target); err != nil {
+		return domain.NewValidationError("invalid patch document")
+	}
+	return nil
+}
+
+// beerStyleDocuments returns two independent generic JSON copies of current:
+// one to patch and one to compare the result against.
+func beerStyleDocuments(current domain.BeerStyle) (map[string]interface{}, map[string]interface{}, error) {
+	raw, err := json.Marshal(current)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var original, document map[string]interface{}
+	if err := json.Unmarshal(raw, &original); err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return nil, nil, err
+	}
+	return original, document, nil
+}
+
+func beerStyleFromPatchedDocument(current domain.BeerStyle, original map[string]interface{}, patched interface{}) (domain.BeerStyle, error) {
+	document, ok := patched.(map[string]interface{})
+	if !ok {
+		return current, domain.NewValidationError("patched beer style must be a JSON object")
+	}
+
+	for _, field := range beerStyleReadOnlyFields {
+		if !reflect.DeepEqual(original[field], document[field]) {
+			return current, domain.NewValidationError("field '%s' is read-only", field)
+		}
+	}
+
+	for field := range document {
+		if _, ok := original[field]; !ok {
+			return current, domain.NewValidationError("unknown field '%s'", field)
+		}
+	}
+
+	name, ok := document["name"].(string)
+	if !ok || strings.TrimSpace(name) == "" {
+		return current, domain.NewValidationError("name must be a non-empty string")
+	}
+	tempMin, ok := document["temp_min"].(float64)
+	if !ok {
+		return current, domain.NewValidationError("temp_min must be a number")
+	}
+	tempMax, ok := document["temp_max"].(float64)
+	if !ok {
+		return current, domain.NewValidationError("temp_max must be a number")
+	}
+
+	current.Name = name
+	current.TempMin = tempMin
+	current.TempMax = tempMax
+	return current, nil
+}
@@ -2,47 +2,40 @@ package service
 
 import (
 	"backend-test/internal/domain"
-	"database/sql"
-	"errors"
+	"context"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
 
+// MaxCityNameLength bounds the city sent to the weather provider.
+const MaxCityNameLength = 100
+
 type ValidationService struct {
-	beerService BeerServiceInterface
+	beerService   BeerServiceInterface
+	updateService UpdateServiceInterface
 }
 
-func NewValidationService(beerService BeerServiceInterface) *ValidationService {
+func NewValidationService(beerService BeerServiceInterface, updateService UpdateServiceInterface) *ValidationService {
 	return &ValidationService{
-		beerService: beerService,
-	}
-}
-
-func (vs *ValidationService) isNoRowsError(err error) bool {
-	if err == nil {
-		return false
+		beerService:   beerService,
+		updateService: updateService,
 	}
-
-	return errors.Is(err, sql.ErrNoRows) || strings.Contains(err.Error(), "no rows in result set")
-}
-
-func (vs *ValidationService) IsNoRowsError(err error) bool {
-	return vs.isNoRowsError(err)
 }
 
 func (vs *ValidationService) ValidateTemperatureRange(beerStyle domain.BeerStyle) error {
 	if beerStyle.TempMin < -90 || beerStyle.TempMin > 60 {
-		return fmt.Errorf("minimum temperature (%.1f) must be between -90°C and 60°C", beerStyle.TempMin)
+		return domain.NewValidationError("minimum temperature (%.1f) must be between -90°C and 60°C", beerStyle.TempMin)
 	}
 
 	if beerStyle.TempMax < -90 || beerStyle.TempMax > 60 {
-		return fmt.Errorf("maximum temperature (%.1f) must be between -90°C and 60°C", beerStyle.TempMax)
+		return domain.NewValidationError("maximum temperature (%.1f) must be between -90°C and 60°C", beerStyle.TempMax)
 	}
 
 	if beerStyle.TempMin >= beerStyle.TempMax {
-		return fmt.Errorf("minimum temperature (%.1f) must be less than maximum temperature (%.1f)",
+		return domain.NewValidationError("minimum temperature (%.1f) must be less than maximum temperature (%.1f)",
 			beerStyle.TempMin, beerStyle.TempMax)
 	}
 
@@ -51,60 +44,253 @@ func (vs *ValidationService) ValidateTemperatureRange(beerStyle domain.BeerStyle
 
 func (vs *ValidationService) ValidateTemperatureInput(temperature float64) error {
 	if temperature < -90 || temperature > 60 {
-		return fmt.Errorf("temperature (%.1f) must be between -90°C and 60°C", temperature)
+		return domain.NewValidationError("temperature (%.1f) must be between -90°C and 60°C", temperature)
 	}
 
 	return nil
 }
 
-func (vs *ValidationService) ValidateUniqueNameForCreate(name string) error {
-	beerStyles, err := vs.beerService.ListAllBeerStyles()
-	if err != nil {
-		if !vs.isNoRowsError(err) {
-			return fmt.Errorf("failed to check beer styles: %w", err)
+// ValidateWeatherQuery requires either a city name or both coordinates.
+func (vs *ValidationService) ValidateWeatherQuery(query domain.WeatherQuery) error {
+	city := strings.TrimSpace(query.City)
+	hasCoordinates := query.Lat != nil || query.Lon != nil
+
+	switch {
+	case city == "" && !hasCoordinates:
+		return domain.NewValidationError("temperature, city or lat and lon are required")
+	case city != "" && hasCoordinates:
+		return domain.NewValidationError("send either city or lat and lon, not both")
+	case city != "":
+		if utf8.RuneCountInString(city) > MaxCityNameLength {
+			return domain.NewValidationError("city must be at most %d characters", MaxCityNameLength)
 		}
 		return nil
+	case query.Lat == nil || query.Lon == nil:
+		return domain.NewValidationError("lat and lon must be sent together")
+	case *query.Lat < -90 || *query.Lat > 90:
+		return domain.NewValidationError("lat (%.4f) must be between -90 and 90", *query.Lat)
+	case *query.Lon < -180 || *query.Lon > 180:
+		return domain.NewValidationError("lon (%.4f) must be between -180 and 180", *query.Lon)
 	}
 
-	for _, style := range beerStyles {
-		if style.Name == name {
-			return fmt.Errorf("beer style with name '%s' already exists", name)
+	return nil
+}
+
+func (vs *ValidationService) ValidateListParams(params domain.BeerStyleListParams) error {
+	if params.Limit < 1 || params.Limit > MaxBeerStyleListLimit {
+		return domain.NewValidationError("limit must be between 1 and %d", MaxBeerStyleListLimit)
+	}
+
+	if params.SortBy != SortByName && params.SortBy != SortByCreatedAt {
+		return domain.NewValidationError("sort must be one of '%s' or '%s'", SortByName, SortByCreatedAt)
+	}
+
+	if params.SortDir != SortAsc && params.SortDir != SortDesc {
+		return domain.NewValidationError("order must be '%s' or '%s'", SortAsc, SortDesc)
+	}
+
+	if params.Temperature != nil {
+		if err := vs.ValidateTemperatureInput(*params.Temperature); err != nil {
+			return err
 		}
 	}
 
+	if params.After != nil && params.After.SortBy != params.SortBy {
+		return domain.NewValidationError("cursor does not match sort '%s'", params.SortBy)
+	}
+
 	return nil
 }
 
-func (vs *ValidationService) ValidateUniqueNameForUpdate(name string, excludeUUID string) error {
-	if name == "" {
+// bulkNameOwner records who holds a name key while a batch is being
+// validated: an existing style, or the batch operation at index that claims it.
+type bulkNameOwner struct {
+	uuid  string
+	index int
+}
+
+// bulkNames tracks who holds each name while a batch is validated. Names are
+// compared by the keys the database computed for them, so two names collide
+// here exactly when the unique index would reject them.
+type bulkNames struct {
+	keys   map[string]string
+	owners map[string]bulkNameOwner
+}
+
+func (n bulkNames) key(name string) string {
+	if key, ok := n.keys[name]; ok {
+		return key
+	}
+	return name
+}
+
+func (n bulkNames) claim(name string, owner bulkNameOwner) {
+	n.owners[n.key(name)] = owner
+}
+
+func (n bulkNames) release(name string) {
+	delete(n.owners, n.key(name))
+}
+
+// available reports a conflict when name is held by anyone other than the
+// style excludeUUID.
+func (n bulkNames) available(name, excludeUUID string) error {
+	owner, taken := n.owners[n.key(name)]
+	if !taken || (excludeUUID != "" && owner.uuid == excludeUUID) {
 		return nil
 	}
+	if owner.index >= 0 {
+		return domain.NewConflictError("name '%s' is already used by operation %d in this batch", name, owner.index)
+	}
+	return domain.NewConflictError("beer style with name '%s' already exists", name)
+}
 
-	beerStyles, err := vs.beerService.ListAllBeerStyles()
-	if err != nil {
-		if !vs.isNoRowsError(err) {
-			return fmt.Errorf("failed to check beer styles: %w", err)
+// bulkNameCandidates lists every name a batch can compare: the catalog's and
+// the ones the operations set.
+func bulkNameCandidates(beerStyles []domain.BeerStyle, operations []domain.BeerStyleBulkOperation) []string {
+	names := make([]string, 0, len(beerStyles)+len(operations))
+	for _, style := range beerStyles {
+		names = append(names, style.Name)
+	}
+	for _, operation := range operations {
+		if operation.Name != nil {
+			names = append(names, *operation.Name)
 		}
-		return nil
 	}
+	return names
+}
+
+// ValidateBulkOperations validates a batch against a single snapshot of the
+// catalog, replaying the operations in order so later ones see the effects
+// of earlier ones, such as a name freed by a rename or claimed by a create in
+// the same batch. Names are compared by the keys the database index uses,
+// ignoring case and accents. It returns one error slot per operation.
+func (vs *ValidationService) ValidateBulkOperations(ctx context.Context, operations []domain.BeerStyleBulkOperation) ([]error, error) {
+	beerStyles, err := vs.beerService.ListAllBeerStyles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check beer styles: %w", err)
+	}
+
+	keys, err := vs.beerService.BeerStyleNameKeys(ctx, bulkNameCandidates(beerStyles, operations))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check beer style names: %w", err)
+	}
+
+	return vs.ValidateBulkOperationsAgainst(beerStyles, keys, operations), nil
+}
 
+// ValidateBulkOperationsAgainst validates a batch like ValidateBulkOperations
+// against a catalog snapshot the caller already read, so a plan built from
+// that snapshot and its validation cannot disagree. keys must hold the
+// database name key of every catalog and operation name.
+func (vs *ValidationService) ValidateBulkOperationsAgainst(beerStyles []domain.BeerStyle, keys map[string]string, operations []domain.BeerStyleBulkOperation) []error {
+	byUUID := make(map[string]domain.BeerStyle, len(beerStyles))
+	names := bulkNames{keys: keys, owners: make(map[string]bulkNameOwner, len(beerStyles))}
 	for _, style := range beerStyles {
-		if style.Name == name && style.UUID != excludeUUID {
-			return fmt.Errorf("beer style with name '%s' already exists", name)
+		byUUID[style.UUID] = style
+		names.claim(style.Name, bulkNameOwner{uuid: style.UUID, index: -1})
+	}
+
+	errs := make([]error, len(operations))
+	for i, operation := range operations {
+		errs[i] = vs.validateBulkOperation(i, operation, byUUID, names)
+	}
+
+	return errs
+}
+
+func (vs *ValidationService) validateBulkOperation(index int, operation domain.BeerStyleBulkOperation, byUUID map[string]domain.BeerStyle, names bulkNames) error {
+	switch operation.Op {
+	case domain.BulkOperationCreate:
+		if operation.Name == nil || *operation.Name == "" {
+			return domain.NewValidationError("name is required")
+		}
+		if operation.TempMin == nil || operation.TempMax == nil {
+			return domain.NewValidationError("temp_min and temp_max are required")
+		}
+
+		candidate := domain.BeerStyle{Name: *operation.Name, TempMin: *operation.TempMin, TempMax: *operation.TempMax}
+		if err := vs.ValidateTemperatureRange(candidate); err != nil {
+			return err
+		}
+		if err := names.available(candidate.Name, ""); err != nil {
+			return err
+		}
+
+		names.claim(candidate.Name, bulkNameOwner{index: index})
+		return nil
+
+	case domain.BulkOperationUpdate:
+		current, err := vs.bulkTarget(operation, byUUID)
+		if err != nil {
+			return err
+		}
+		if operation.Name != nil && *operation.Name == "" {
+			return domain.NewValidationError("name cannot be empty")
 		}
+
+		updated := current
+		updates := domain.BeerStyleUpdateRequest{Name: operation.Name, TempMin: operation.TempMin, TempMax: operation.TempMax}
+		if !vs.updateService.ApplyBeerStyleUpdates(&updated, updates) {
+			return nil
+		}
+
+		if err := vs.ValidateTemperatureRange(updated); err != nil {
+			return err
+		}
+		if updated.Name != current.Name {
+			if err := names.available(updated.Name, current.UUID); err != nil {
+				return err
+			}
+			names.release(current.Name)
+			names.claim(updated.Name, bulkNameOwner{uuid: current.UUID, index: index})
+		}
+
+		updated.Version++
+		byUUID[current.UUID] = updated
+		return nil
+
+	case domain.BulkOperationDelete:
+		current, err := vs.bulkTarget(operation, byUUID)
+		if err != nil {
+			return err
+		}
+
+		names.release(current.Name)
+		delete(byUUID, current.UUID)
+		return nil
+
+	default:
+		return domain.NewValidationError("op must be one of '%s', '%s' or '%s'",
+			domain.BulkOperationCreate, domain.BulkOperationUpdate, domain.BulkOperationDelete)
 	}
+}
 
-	return nil
+func (vs *ValidationService) bulkTarget(operation domain.BeerStyleBulkOperation, byUUID map[string]domain.BeerStyle) (domain.BeerStyle, error) {
+	if err := vs.ValidateUUID(operation.UUID); err != nil {
+		return domain.BeerStyle{}, err
+	}
+
+	current, ok := byUUID[operation.UUID]
+	if !ok {
+		return domain.BeerStyle{}, domain.NewNotFoundError("beer style not found")
+	}
+
+	if operation.Version != nil && *operation.Version != current.Version {
+		return domain.BeerStyle{}, domain.NewPreconditionFailedError("beer style was modified by another request")
+	}
+
+	return current, nil
 }
 
 func (vs *ValidationService) ValidateUUID(uuidStr string) error {
 	if uuidStr == "" {
-		return fmt.Errorf("UUID cannot be empty")
+		return domain.NewValidationError("UUID cannot be empty")
 	}
 
 	_, err := uuid.Parse(uuidStr)
 	if err != nil {
-		return fmt.Errorf("invalid UUID format: %s", uuidStr)
+		return domain.NewValidationError("invalid UUID format: %s", uuidStr)
 	}
 
 	return nil
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"backend-test/internal/domain"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidationService_ValidateWeatherQuery(t *testing.T) {
+	lat, lon, badLat, badLon := -23.55, -46.63, 95.0, 200.0
+
+	tests := []struct {
+		name  string
+		query domain.WeatherQuery
+		valid bool
+	}{
+		{"city", domain.WeatherQuery{City: "São Paulo"}, true},
+		{"coordinates", domain.WeatherQuery{Lat: &lat, Lon: &lon}, true},
+		{"empty", domain.WeatherQuery{}, false},
+		{"blank city", domain.WeatherQuery{City: "   "}, false},
+		{"city too long", domain.WeatherQuery{City: strings.Repeat("a", MaxCityNameLength+1)}, false},
+		{"city and coordinates", domain.WeatherQuery{City: "São Paulo", Lat: &lat, Lon: &lon}, false},
+		{"longitude missing", domain.WeatherQuery{Lat: &lat}, false},
+		{"latitude out of range", domain.WeatherQuery{Lat: &badLat, Lon: &lon}, false},
+		{"longitude out of range", domain.WeatherQuery{Lat: &lat, Lon: &badLon}, false},
+	}
+
+	vs := NewValidationService(nil, NewUpdateService())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := vs.ValidateWeatherQuery(tt.query)
+			if tt.valid && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, domain.ErrValidation) {
+				t.Errorf("Expected validation error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,343 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/vingarcia/ksql"
+)
+
+//go:embed migrations/*.sql
+var embeddedMigrations embed.FS
+
+var (
+	ErrMigrationModified = errors.New("applied migration was modified")
+	ErrMigrationUnknown  = errors.New("applied migration is missing from the binary")
+	ErrMigrationNoDown   = errors.New("migration has no down script")
+)
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+const (
+	createMigrationsTableQuery = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+	// The lock is scoped to the transaction, so it is released even if the
+	// process dies halfway through.
+	migrationLockQuery = `SELECT pg_advisory_xact_lock(hashtext('backend-test:schema_migrations'))`
+)
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%03d_%s", m.Version, m.Name)
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	// Modified is set when the applied checksum no longer matches the file.
+	Modified bool
+	// Unknown is set when the database has a version the binary lacks.
+	Unknown bool
+}
+
+type appliedMigration struct {
+	Version   int64     `ksql:"version"`
+	Name      string    `ksql:"name"`
+	Checksum  string    `ksql:"checksum"`
+	AppliedAt time.Time `ksql:"applied_at"`
+}
+
+// Migrator applies the SQL migrations embedded in the binary and records
+// them in schema_migrations. Every operation runs in a single transaction
+// holding an advisory lock, so concurrent replicas apply each migration once.
+type Migrator struct {
+	db         ksql.Provider
+	migrations []Migration
+}
+
+func NewMigrator(db ksql.Provider) (*Migrator, error) {
+	migrationsFS, err := fs.Sub(embeddedMigrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return newMigrator(db, migrationsFS)
+}
+
+func newMigrator(db ksql.Provider, fsys fs.FS) (*Migrator, error) {
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up applies every pending migration in version order and returns the ones
+// it applied. It refuses to run if an applied migration was edited or is
+// unknown to this binary.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(ctx, func(tx ksql.Provider) error {
+		records, err := m.appliedMigrations(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		if err := m.verify(records); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := records[migration.Version]; ok {
+				continue
+			}
+
+			if _, err := tx.Exec(ctx, migration.Up); err != nil {
+				return fmt.Errorf("failed to apply migration %s: %w", migration, err)
+			}
+
+			_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+				migration.Version, migration.Name, migration.Checksum)
+			if err != nil {
+				return fmt.Errorf("failed to record migration %s: %w", migration, err)
+			}
+
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// Down reverts the last steps applied migrations, newest first.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := m.withLock(ctx, func(tx ksql.Provider) error {
+		records, err := m.appliedMigrations(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		if err := m.verify(records); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := records[migration.Version]; !ok {
+				continue
+			}
+
+			if migration.Down == "" {
+				return fmt.Errorf("%w: %s", ErrMigrationNoDown, migration)
+			}
+
+			if _, err := tx.Exec(ctx, migration.Down); err != nil {
+				return fmt.Errorf("failed to revert migration %s: %w", migration, err)
+			}
+
+			if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version); err != nil {
+				return fmt.Errorf("failed to unrecord migration %s: %w", migration, err)
+			}
+
+			reverted = append(reverted, migration)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reverted, nil
+}
+
+// Status lists every known migration, plus any applied version the binary
+// does not know about.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+
+	err := m.withLock(ctx, func(tx ksql.Provider) error {
+		records, err := m.appliedMigrations(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		known := make(map[int64]bool, len(m.migrations))
+		for _, migration := range m.migrations {
+			known[migration.Version] = true
+
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if record, ok := records[migration.Version]; ok {
+				appliedAt := record.AppliedAt
+				status.Applied = true
+				status.AppliedAt = &appliedAt
+				status.Modified = record.Checksum != migration.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+
+		for version, record := range records {
+			if known[version] {
+				continue
+			}
+			appliedAt := record.AppliedAt
+			statuses = append(statuses, MigrationStatus{
+				Version:   version,
+				Name:      record.Name,
+				Applied:   true,
+				AppliedAt: &appliedAt,
+				Unknown:   true,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(tx ksql.Provider) error) error {
+	return m.db.Transaction(ctx, func(tx ksql.Provider) error {
+		if _, err := tx.Exec(ctx, migrationLockQuery); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, createMigrationsTableQuery); err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+
+		return fn(tx)
+	})
+}
+
+func (m *Migrator) appliedMigrations(ctx context.Context, tx ksql.Provider) (map[int64]appliedMigration, error) {
+	var records []appliedMigration
+	err := tx.Query(ctx, &records, "SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	applied := make(map[int64]appliedMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) verify(records map[int64]appliedMigration) error {
+	known := make(map[int64]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	for version, record := range records {
+		migration, ok := known[version]
+		if !ok {
+			return fmt.Errorf("%w: %03d_%s", ErrMigrationUnknown, version, record.Name)
+		}
+		if record.Checksum != migration.Checksum {
+			return fmt.Errorf("%w: %s", ErrMigrationModified, migration)
+		}
+	}
+
+	return nil
+}
+
+// loadMigrations pairs NNN_name.up.sql with its optional NNN_name.down.sql.
+// The checksum covers the up script, which is what was run when applied.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, file := range files {
+		matches := migrationFilePattern.FindStringSubmatch(path.Base(file))
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name %q: expected NNN_name.up.sql or NNN_name.down.sql", file)
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", file, err)
+		}
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %q and %q", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			sum := sha256.Sum256(content)
+			migration.Up = string(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %s has no up script", migration)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/vingarcia/ksql"
+)
+
+func testMigrationsFS() fstest.MapFS {
+	return fstest.MapFS{
+		"001_create_table.up.sql":   {Data: []byte("CREATE TABLE things (id INT);")},
+		"001_create_table.down.sql": {Data: []byte("DROP TABLE things;")},
+		"002_seed.up.sql":           {Data: []byte("INSERT INTO things VALUES (1);")},
+		"002_seed.down.sql":         {Data: []byte("DELETE FROM things;")},
+		"003_index.up.sql":          {Data: []byte("CREATE INDEX things_id ON things (id);")},
+	}
+}
+
+// newMigrationsDB fakes schema_migrations with the given applied records and
+// captures every statement executed.
+func newMigrationsDB(applied []appliedMigration, executed *[]string) ksql.Mock {
+	return ksql.Mock{
+		ExecFn: func(ctx context.Context, query string, params ...interface{}) (ksql.Result, error) {
+			*executed = append(*executed, query)
+			return ksql.NewMockResult(0, 1), nil
+		},
+		QueryFn: func(ctx context.Context, records interface{}, query string, params ...interface{}) error {
+			*records.(*[]appliedMigration) = append([]appliedMigration(nil), applied...)
+			return nil
+		},
+	}
+}
+
+func newTestMigrator(t *testing.T, db ksql.Provider) *Migrator {
+	t.Helper()
+
+	migrator, err := newMigrator(db, testMigrationsFS())
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+	return migrator
+}
+
+func appliedRecord(m Migration) appliedMigration {
+	return appliedMigration{Version: m.Version, Name: m.Name, Checksum: m.Checksum, AppliedAt: time.Now()}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations(testMigrationsFS())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(migrations) != 3 {
+		t.Fatalf("Expected 3 migrations, got %d", len(migrations))
+	}
+
+	if migrations[0].String() != "001_create_table" || migrations[0].Down != "DROP TABLE things;" || len(migrations[0].Checksum) != 64 {
+		t.Errorf("Unexpected first migration: %+v", migrations[0])
+	}
+
+	if migrations[2].Down != "" {
+		t.Errorf("Expected migration without down script, got %q", migrations[2].Down)
+	}
+
+	invalid := []fstest.MapFS{
+		{"create_table.up.sql": {Data: []byte("SELECT 1;")}},
+		{"001_create_table.down.sql": {Data: []byte("SELECT 1;")}},
+		{"001_a.up.sql": {Data: []byte("SELECT 1;")}, "001_b.up.sql": {Data: []byte("SELECT 1;")}},
+	}
+	for _, fsys := range invalid {
+		if _, err := loadMigrations(fsys); err == nil {
+			t.Errorf("Expected error for %v", fsys)
+		}
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrator, err := NewMigrator(ksql.Mock{})
+	if err != nil {
+		t.Fatalf("Expected embedded migrations to load, got %v", err)
+	}
+
+	for _, migration := range migrator.Migrations() {
+		if migration.Down == "" {
+			t.Errorf("Expected migration %s to have a down script", migration)
+		}
+	}
+}
+
+func TestMigrator_UpAppliesPendingMigrations(t *testing.T) {
+	var executed []string
+	migrations, _ := loadMigrations(testMigrationsFS())
+	db := newMigrationsDB([]appliedMigration{appliedRecord(migrations[0])}, &executed)
+
+	applied, err := newTestMigrator(t, db).Up(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(applied) != 2 || applied[0].Version != 2 || applied[1].Version != 3 {
+		t.Fatalf("Expected migrations 2 and 3 to be applied, got %+v", applied)
+	}
+
+	if executed[0] != migrationLockQuery {
+		t.Errorf("Expected the advisory lock to be taken first, got %q", executed[0])
+	}
+
+	joined := strings.Join(executed, "\n")
+	if strings.Contains(joined, "CREATE TABLE things") {
+		t.Error("Expected the already applied migration not to run again")
+	}
+	if strings.Count(joined, "INSERT INTO schema_migrations") != 2 {
+		t.Errorf("Expected 2 migrations to be recorded, got:\n%s", joined)
+	}
+}
+
+func TestMigrator_RefusesInconsistentHistory(t *testing.T) {
+	migrations, _ := loadMigrations(testMigrationsFS())
+
+	modified := appliedRecord(migrations[0])
+	modified.Checksum = strings.Repeat("0", 64)
+
+	tests := []struct {
+		name        string
+		applied     []appliedMigration
+		expectedErr error
+	}{
+		{"edited migration", []appliedMigration{modified}, ErrMigrationModified},
+		{"unknown migration", []appliedMigration{{Version: 99, Name: "future", Checksum: "x"}}, ErrMigrationUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var executed []string
+			_, err := newTestMigrator(t, newMigrationsDB(tt.applied, &executed)).Up(context.Background())
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestMigrator_DownRevertsNewestFirst(t *testing.T) {
+	migrations, _ := loadMigrations(testMigrationsFS())
+	applied := []appliedMigration{appliedRecord(migrations[0]), appliedRecord(migrations[1])}
+
+	var executed []string
+	reverted, err := newTestMigrator(t, newMigrationsDB(applied, &executed)).Down(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(reverted) != 2 || reverted[0].Version != 2 || reverted[1].Version != 1 {
+		t.Errorf("Expected migrations 2 and 1 to be reverted, got %+v", reverted)
+	}
+
+	applied = append(applied, appliedRecord(migrations[2]))
+	if _, err := newTestMigrator(t, newMigrationsDB(applied, &executed)).Down(context.Background(), 1); !errors.Is(err, ErrMigrationNoDown) {
+		t.Errorf("Expected %v, got %v", ErrMigrationNoDown, err)
+	}
+}
+
+func TestMigrator_Status(t *testing.T) {
+	migrations, _ := loadMigrations(testMigrationsFS())
+	modified := appliedRecord(migrations[1])
+	modified.Checksum = "changed"
+	applied := []appliedMigration{appliedRecord(migrations[0]), modified, {Version: 7, Name: "removed"}}
+
+	var executed []string
+	statuses, err := newTestMigrator(t, newMigrationsDB(applied, &executed)).Status(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(statuses) != 4 {
+		t.Fatalf("Expected 4 statuses, got %+v", statuses)
+	}
+
+	if !statuses[0].Applied || statuses[0].Modified {
+		t.Errorf("Expected first migration applied and unchanged, got %+v", statuses[0])
+	}
+	if !statuses[1].Modified {
+		t.Errorf("Expected second migration to be flagged as modified, got %+v", statuses[1])
+	}
+	if statuses[2].Applied {
+		t.Errorf("Expected third migration to be pending, got %+v", statuses[2])
+	}
+	if !statuses[3].Unknown || statuses[3].Version != 7 {
+		t.Errorf("Expected unknown version 7 last, got %+v", statuses[3])
+	}
+}
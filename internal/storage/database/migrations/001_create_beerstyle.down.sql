@@ -0,0 +1,2 @@
+-- Remove a tabela beer_styles
+DROP TABLE IF EXISTS beer_styles;
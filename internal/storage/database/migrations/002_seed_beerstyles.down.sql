@@ -0,0 +1,7 @@
+-- Remove os estilos de cerveja inseridos pelo seed
+DELETE FROM beer_styles WHERE name IN (
+    'IPA', 'Lager', 'Stout', 'Pilsner', 'Wheat Beer',
+    'Porter', 'Pale Ale', 'Belgian Dubbel', 'Saison', 'Barleywine',
+    'Arctic Lager', 'Frozen Ale', 'Ice Beer', 'Winter Porter', 'Hot Weather Lager',
+    'Desert Ale', 'Tropical IPA', 'Extreme Cold', 'Warm Climate', 'Zero Point'
+);
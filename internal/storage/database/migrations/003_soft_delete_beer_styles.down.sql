@@ -0,0 +1,6 @@
+-- Remove o soft delete; estilos apagados são removidos definitivamente
+DROP INDEX IF EXISTS beer_styles_deleted_at_idx;
+DROP INDEX IF EXISTS beer_styles_name_active_key;
+DELETE FROM beer_styles WHERE deleted_at IS NOT NULL;
+ALTER TABLE beer_styles ADD CONSTRAINT beer_styles_name_key UNIQUE (name);
+ALTER TABLE beer_styles DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,9 @@
+-- Adiciona soft delete aos estilos de cerveja
+ALTER TABLE beer_styles ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+
+-- O nome só precisa ser único entre os estilos não apagados
+ALTER TABLE beer_styles DROP CONSTRAINT IF EXISTS beer_styles_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS beer_styles_name_active_key ON beer_styles (name) WHERE deleted_at IS NULL;
+
+-- Acelera a remoção definitiva dos estilos apagados há mais tempo que a retenção
+CREATE INDEX IF NOT EXISTS beer_styles_deleted_at_idx ON beer_styles (deleted_at) WHERE deleted_at IS NOT NULL;
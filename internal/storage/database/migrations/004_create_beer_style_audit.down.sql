@@ -0,0 +1,2 @@
+-- Remove o histórico de alterações
+DROP TABLE IF EXISTS beer_style_audit;
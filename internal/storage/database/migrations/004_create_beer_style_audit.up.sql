@@ -0,0 +1,12 @@
+-- Histórico de alterações dos estilos de cerveja, com o antes/depois de cada campo
+CREATE TABLE IF NOT EXISTS beer_style_audit (
+    id BIGSERIAL PRIMARY KEY,
+    beer_style_uuid UUID NOT NULL,
+    operation VARCHAR(16) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- O histórico é sempre lido por estilo, do mais recente para o mais antigo
+CREATE INDEX IF NOT EXISTS beer_style_audit_style_idx ON beer_style_audit (beer_style_uuid, id DESC);
@@ -0,0 +1,2 @@
+-- Remove o controle de versão dos estilos de cerveja
+ALTER TABLE beer_styles DROP COLUMN IF EXISTS version;
@@ -0,0 +1,2 @@
+-- Versão do registro para controle de concorrência otimista (ETag / If-Match)
+ALTER TABLE beer_styles ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
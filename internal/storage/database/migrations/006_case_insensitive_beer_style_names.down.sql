@@ -0,0 +1,4 @@
+-- Volta à unicidade exata do nome; a extensão unaccent é mantida
+DROP INDEX IF EXISTS beer_styles_name_key_active_key;
+CREATE UNIQUE INDEX IF NOT EXISTS beer_styles_name_active_key ON beer_styles (name) WHERE deleted_at IS NULL;
+DROP FUNCTION IF EXISTS beer_style_name_key(TEXT);
@@ -0,0 +1,12 @@
+-- Nomes de estilo passam a ser únicos sem diferenciar maiúsculas nem acentos
+-- ("IPA" e "ipa", "Märzen" e "Marzen" conflitam)
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() é STABLE e não pode ser usada em índice; fixar o dicionário
+-- torna o resultado determinístico e permite declarar a função IMMUTABLE
+CREATE OR REPLACE FUNCTION beer_style_name_key(name TEXT) RETURNS TEXT
+    LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+    AS $$ SELECT lower(public.unaccent('public.unaccent'::regdictionary, name)) $$;
+
+DROP INDEX IF EXISTS beer_styles_name_active_key;
+CREATE UNIQUE INDEX IF NOT EXISTS beer_styles_name_key_active_key ON beer_styles (beer_style_name_key(name)) WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+-- Remove as playlists escolhidas por curadores
+DROP TABLE IF EXISTS beer_style_playlists;
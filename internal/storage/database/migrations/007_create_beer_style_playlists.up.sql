@@ -0,0 +1,12 @@
+-- Playlists escolhidas por curadores para cada estilo, usadas antes da busca
+CREATE TABLE IF NOT EXISTS beer_style_playlists (
+    beer_style_uuid UUID NOT NULL REFERENCES beer_styles (uuid) ON DELETE CASCADE,
+    playlist_id VARCHAR(64) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (beer_style_uuid, playlist_id)
+);
+
+-- As playlists de um estilo são sempre lidas da maior para a menor prioridade
+CREATE INDEX IF NOT EXISTS beer_style_playlists_priority_idx ON beer_style_playlists (beer_style_uuid, priority DESC, created_at);
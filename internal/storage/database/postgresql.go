@@ -1,35 +1,20 @@
 package postgres
 
 import (
-	config "backend-test/internal/cmd/server"
 	"context"
-	"log"
-	"sync"
+	"fmt"
 
 	"github.com/vingarcia/ksql"
 	"github.com/vingarcia/ksql/adapters/kpgx"
 )
 
-var (
-	db   *ksql.DB
-	once sync.Once
-)
+func Connect(ctx context.Context, databaseURL string) (*ksql.DB, error) {
+	db, err := kpgx.New(ctx, databaseURL, ksql.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
 
-func GetDB() *ksql.DB {
-	once.Do(func() {
-		dbConnect, err := kpgx.New(context.Background(), config.DATABASE_URL, ksql.Config{})
-		if err != nil {
-			log.Panic(err)
-		}
-		dbConnect.Exec(context.Background(), "set enable_seqscan = off;")
-		db = &dbConnect
-	})
-	return db
-}
+	db.Exec(ctx, "set enable_seqscan = off;")
 
-func CloseDB() error {
-	if db != nil {
-		return db.Close()
-	}
-	return nil
+	return &db, nil
 }
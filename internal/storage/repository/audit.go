@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"backend-test/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vingarcia/ksql"
+)
+
+// recordAudit stores the field-level diff of a change using the caller's
+// transaction, so the change and its audit entry commit or fail together.
+func (u BeerRepository) recordAudit(ctx context.Context, tx ksql.Provider, operation string, before, after *domain.BeerStyle) error {
+	changes, err := json.Marshal(domain.BeerStyleChanges(before, after))
+	if err != nil {
+		return fmt.Errorf("failed to encode audit changes: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, u.insertAuditQuery(), after.UUID, operation, domain.ActorFromContext(ctx), string(changes))
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (u BeerRepository) ListBeerStyleHistory(ctx context.Context, params domain.BeerStyleHistoryParams) ([]domain.BeerStyleAuditEntry, error) {
+	ctx, cancel := u.withTimeout(ctx)
+	defer cancel()
+
+	query, args := u.listBeerStyleHistoryQuery(params)
+
+	var entries []domain.BeerStyleAuditEntry
+	err := u.db.Query(ctx, &entries, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (BeerRepository) insertAuditQuery() string {
+	return `
+		INSERT INTO beer_style_audit (beer_style_uuid, operation, actor, changes)
+		VALUES ($1, $2, $3, $4::jsonb);
+	`
+}
+
+func (BeerRepository) listBeerStyleHistoryQuery(params domain.BeerStyleHistoryParams) (string, []interface{}) {
+	args := []interface{}{params.BeerStyleUUID}
+	where := "beer_style_uuid = $1"
+
+	if params.BeforeID > 0 {
+		args = append(args, params.BeforeID)
+		where += fmt.Sprintf(" AND id < $%d", len(args))
+	}
+
+	args = append(args, params.Limit)
+
+	return fmt.Sprintf(`
+		SELECT id, beer_style_uuid, operation, actor, changes, created_at
+		FROM beer_style_audit
+		WHERE %s
+		ORDER BY id DESC
+		LIMIT $%d
+	`, where, len(args)), args
+}
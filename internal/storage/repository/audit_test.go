@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"backend-test/internal/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vingarcia/ksql"
+)
+
+type auditRecorder struct {
+	params [][]interface{}
+	err    error
+}
+
+func (r *auditRecorder) exec(ctx context.Context, query string, params ...interface{}) (ksql.Result, error) {
+	if strings.Contains(query, "beer_style_audit") {
+		r.params = append(r.params, params)
+	}
+	return ksql.NewMockResult(0, 1), r.err
+}
+
+func TestBeerRepository_UpdateRecordsFieldDiff(t *testing.T) {
+	current := domain.BeerStyle{UUID: "uuid-1", Name: "IPA", TempMin: 7.0, TempMax: 10.0}
+	recorder := &auditRecorder{}
+
+	db := ksql.Mock{
+		QueryOneFn: func(ctx context.Context, record interface{}, query string, params ...interface{}) error {
+			beerStyle := record.(*domain.BeerStyle)
+			if strings.Contains(query, "FOR UPDATE") {
+				*beerStyle = current
+				return nil
+			}
+			*beerStyle = domain.BeerStyle{UUID: "uuid-1", Name: "IPA", TempMin: 7.0, TempMax: 12.0}
+			return nil
+		},
+		ExecFn: recorder.exec,
+	}
+
+	repo := NewBeerRepository(db, 0)
+	ctx := domain.WithActor(context.Background(), "maria")
+
+	if _, err := repo.UpdateBeerStyle(ctx, domain.BeerStyle{UUID: "uuid-1", Name: "IPA", TempMin: 7.0, TempMax: 12.0}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(recorder.params) != 1 {
+		t.Fatalf("Expected 1 audit entry, got %d", len(recorder.params))
+	}
+
+	params := recorder.params[0]
+	if params[0] != "uuid-1" || params[1] != domain.AuditOperationUpdate || params[2] != "maria" {
+		t.Errorf("Unexpected audit params: %v", params[:3])
+	}
+
+	var changes map[string]domain.FieldChange
+	if err := json.Unmarshal([]byte(params[3].(string)), &changes); err != nil {
+		t.Fatalf("Failed to decode changes: %v", err)
+	}
+
+	if len(changes) != 1 || changes["temp_max"].Before != 10.0 || changes["temp_max"].After != 12.0 {
+		t.Errorf("Expected only temp_max 10 -> 12, got %+v", changes)
+	}
+}
+
+func TestBeerRepository_CreateFailsWhenAuditFails(t *testing.T) {
+	recorder := &auditRecorder{err: errors.New("audit table missing")}
+
+	db := ksql.Mock{
+		QueryOneFn: func(ctx context.Context, record interface{}, query string, params ...interface{}) error {
+			*record.(*domain.BeerStyle) = domain.BeerStyle{UUID: "uuid-1", Name: "IPA", TempMin: 7.0, TempMax: 10.0}
+			return nil
+		},
+		ExecFn: recorder.exec,
+	}
+
+	_, err := NewBeerRepository(db, 0).CreateBeerStyle(context.Background(), domain.BeerStyle{Name: "IPA", TempMin: 7.0, TempMax: 10.0})
+	if err == nil {
+		t.Fatal("Expected the create to fail with its audit entry")
+	}
+
+	if recorder.params[0][2] != domain.AnonymousActor {
+		t.Errorf("Expected anonymous actor, got %v", recorder.params[0][2])
+	}
+}
+
+func TestBeerRepository_UpdateRejectsStaleVersion(t *testing.T) {
+	recorder := &auditRecorder{}
+	updates := 0
+
+	db := ksql.Mock{
+		QueryOneFn: func(ctx context.Context, record interface{}, query string, params ...interface{}) error {
+			if strings.Contains(query, "FOR UPDATE") {
+				*record.(*domain.BeerStyle) = domain.BeerStyle{UUID: "uuid-1", Name: "IPA", TempMin: 7.0, TempMax: 10.0, Version: 5}
+				return nil
+			}
+			updates++
+			return nil
+		},
+		ExecFn: recorder.exec,
+	}
+
+	_, err := NewBeerRepository(db, 0).UpdateBeerStyle(context.Background(), domain.BeerStyle{UUID: "uuid-1", Name: "IPA", TempMin: 7.0, TempMax: 12.0, Version: 4})
+	if !errors.Is(err, domain.ErrPreconditionFailed) {
+		t.Fatalf("Expected precondition failed error, got %v", err)
+	}
+
+	if updates != 0 || len(recorder.params) != 0 {
+		t.Errorf("Expected no write and no audit entry, got %d updates and %d entries", updates, len(recorder.params))
+	}
+}
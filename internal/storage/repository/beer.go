@@ -2,21 +2,49 @@ package repository
 
 import (
 	"backend-test/internal/domain"
-	postgres "backend-test/internal/storage/database"
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/vingarcia/ksql"
 )
 
-type BeerRepository struct{}
+// uniqueViolation is the Postgres SQLSTATE raised when a write collides with
+// a unique index, such as the case-insensitive index on active style names.
+const uniqueViolation = "23505"
 
-func (u BeerRepository) ListAllBeerStyles() ([]domain.BeerStyle, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+type BeerRepository struct {
+	db           ksql.Provider
+	queryTimeout time.Duration
+}
+
+func NewBeerRepository(db ksql.Provider, queryTimeout time.Duration) *BeerRepository {
+	return &BeerRepository{
+		db:           db,
+		queryTimeout: queryTimeout,
+	}
+}
+
+var beerStyleSortColumns = map[string]string{
+	"name":       "name",
+	"created_at": "created_at",
+}
 
-	db := postgres.GetDB()
+var beerStyleCursorCasts = map[string]string{
+	"name":       "text",
+	"created_at": "timestamp",
+}
+
+func (u BeerRepository) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
+	ctx, cancel := u.withTimeout(ctx)
+	defer cancel()
 
 	var beerStyles []domain.BeerStyle
-	err := db.Query(ctx, &beerStyles, u.getAllBeerStylesQuery())
+	err := u.db.Query(ctx, &beerStyles, u.getAllBeerStylesQuery())
 	if err != nil {
 		return nil, err
 	}
@@ -24,14 +52,34 @@ func (u BeerRepository) ListAllBeerStyles() ([]domain.BeerStyle, error) {
 	return beerStyles, nil
 }
 
-func (u BeerRepository) CreateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (u BeerRepository) ListBeerStyles(ctx context.Context, params domain.BeerStyleListParams) ([]domain.BeerStyle, error) {
+	ctx, cancel := u.withTimeout(ctx)
 	defer cancel()
 
-	db := postgres.GetDB()
+	query, args := u.listBeerStylesQuery(params)
+
+	var beerStyles []domain.BeerStyle
+	err := u.db.Query(ctx, &beerStyles, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return beerStyles, nil
+}
+
+func (u BeerRepository) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	ctx, cancel := u.withTimeout(ctx)
+	defer cancel()
 
 	var createdBeerStyle domain.BeerStyle
-	err := db.QueryOne(ctx, &createdBeerStyle, u.createBeerStyleQuery(), beerStyle.Name, beerStyle.TempMin, beerStyle.TempMax)
+	err := u.db.Transaction(ctx, func(tx ksql.Provider) error {
+		err := tx.QueryOne(ctx, &createdBeerStyle, u.createBeerStyleQuery(), beerStyle.Name, beerStyle.TempMin, beerStyle.TempMax)
+		if err != nil {
+			return translateNameConflict(err, beerStyle.Name)
+		}
+
+		return u.recordAudit(ctx, tx, domain.AuditOperationCreate, nil, &createdBeerStyle)
+	})
 	if err != nil {
 		return domain.BeerStyle{}, err
 	}
@@ -39,71 +87,296 @@ func (u BeerRepository) CreateBeerStyle(beerStyle domain.BeerStyle) (domain.Beer
 	return createdBeerStyle, nil
 }
 
-func (u BeerRepository) GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (u BeerRepository) GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	ctx, cancel := u.withTimeout(ctx)
 	defer cancel()
 
-	db := postgres.GetDB()
-
 	var beerStyle domain.BeerStyle
-	err := db.QueryOne(ctx, &beerStyle, u.getBeerStyleByUUIDQuery(), beerUUID)
+	err := u.db.QueryOne(ctx, &beerStyle, u.getBeerStyleByUUIDQuery(), beerUUID)
 	if err != nil {
-		return domain.BeerStyle{}, err
+		return domain.BeerStyle{}, translateError(err)
 	}
 
 	return beerStyle, nil
 }
 
-func (u BeerRepository) UpdateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (u BeerRepository) GetBeerStyleByUUIDIncludingDeleted(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	ctx, cancel := u.withTimeout(ctx)
 	defer cancel()
 
-	db := postgres.GetDB()
+	var beerStyle domain.BeerStyle
+	err := u.db.QueryOne(ctx, &beerStyle, u.getBeerStyleByUUIDIncludingDeletedQuery(), beerUUID)
+	if err != nil {
+		return domain.BeerStyle{}, translateError(err)
+	}
+
+	return beerStyle, nil
+}
+
+// UpdateBeerStyle writes beerStyle only if the stored version still equals
+// beerStyle.Version, failing with domain.ErrPreconditionFailed otherwise.
+func (u BeerRepository) UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+	ctx, cancel := u.withTimeout(ctx)
+	defer cancel()
 
 	var updatedBeerStyle domain.BeerStyle
-	err := db.QueryOne(ctx, &updatedBeerStyle, u.updateBeerStyleQuery(),
-		beerStyle.Name, beerStyle.TempMin, beerStyle.TempMax, beerStyle.UUID)
+	err := u.db.Transaction(ctx, func(tx ksql.Provider) error {
+		current, err := u.lockBeerStyle(ctx, tx, beerStyle.UUID)
+		if err != nil {
+			return err
+		}
+
+		if current.DeletedAt == nil && current.Version != beerStyle.Version {
+			return domain.NewPreconditionFailedError("beer style was modified by another request")
+		}
+
+		err = tx.QueryOne(ctx, &updatedBeerStyle, u.updateBeerStyleQuery(),
+			beerStyle.Name, beerStyle.TempMin, beerStyle.TempMax, beerStyle.UUID, beerStyle.Version)
+		if err != nil {
+			return translateNameConflict(err, beerStyle.Name)
+		}
+
+		return u.recordAudit(ctx, tx, domain.AuditOperationUpdate, &current, &updatedBeerStyle)
+	})
 	if err != nil {
-		return domain.BeerStyle{}, err
+		return domain.BeerStyle{}, translateError(err)
 	}
 
 	return updatedBeerStyle, nil
 }
 
-func (u BeerRepository) DeleteBeerStyle(beerUUID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (u BeerRepository) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
+	ctx, cancel := u.withTimeout(ctx)
 	defer cancel()
 
-	db := postgres.GetDB()
+	err := u.db.Transaction(ctx, func(tx ksql.Provider) error {
+		current, err := u.lockBeerStyle(ctx, tx, beerUUID)
+		if err != nil {
+			return err
+		}
 
-	_, err := db.Exec(ctx, u.deleteBeerStyleQuery(), beerUUID)
+		var deletedBeerStyle domain.BeerStyle
+		if err := tx.QueryOne(ctx, &deletedBeerStyle, u.deleteBeerStyleQuery(), beerUUID); err != nil {
+			return err
+		}
+
+		return u.recordAudit(ctx, tx, domain.AuditOperationDelete, &current, &deletedBeerStyle)
+	})
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
 	return nil
 }
 
+func (u BeerRepository) RestoreBeerStyle(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	ctx, cancel := u.withTimeout(ctx)
+	defer cancel()
+
+	var restoredBeerStyle domain.BeerStyle
+	err := u.db.Transaction(ctx, func(tx ksql.Provider) error {
+		current, err := u.lockBeerStyle(ctx, tx, beerUUID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.QueryOne(ctx, &restoredBeerStyle, u.restoreBeerStyleQuery(), beerUUID); err != nil {
+			return translateNameConflict(err, current.Name)
+		}
+
+		return u.recordAudit(ctx, tx, domain.AuditOperationRestore, &current, &restoredBeerStyle)
+	})
+	if err != nil {
+		return domain.BeerStyle{}, translateError(err)
+	}
+
+	return restoredBeerStyle, nil
+}
+
+// StreamBeerStyles hands every active style to fn in name order, chunkSize
+// rows at a time, without loading the whole catalog in memory. The query
+// timeout does not apply: the stream lives as long as ctx.
+func (u BeerRepository) StreamBeerStyles(ctx context.Context, chunkSize int, fn func([]domain.BeerStyle) error) error {
+	return u.db.QueryChunks(ctx, ksql.ChunkParser{
+		Query:        u.getAllBeerStylesQuery() + " ORDER BY name, uuid",
+		ChunkSize:    chunkSize,
+		ForEachChunk: fn,
+	})
+}
+
+// lockBeerStyle reads the current row, deleted or not, and holds it until
+// the transaction ends so the audited "before" values cannot go stale.
+// beerStyleNameKeyRow pairs a name with the key the unique index gives it.
+type beerStyleNameKeyRow struct {
+	Name string `ksql:"name"`
+	Key  string `ksql:"name_key"`
+}
+
+// BeerStyleNameKeys asks the database for the key its unique name index
+// gives each name, so callers detect collisions exactly as the index does.
+func (u BeerRepository) BeerStyleNameKeys(ctx context.Context, names []string) (map[string]string, error) {
+	keys := make(map[string]string, len(names))
+	if len(names) == 0 {
+		return keys, nil
+	}
+
+	ctx, cancel := u.withTimeout(ctx)
+	defer cancel()
+
+	var rows []beerStyleNameKeyRow
+	if err := u.db.Query(ctx, &rows, u.beerStyleNameKeysQuery(), names); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		keys[row.Name] = row.Key
+	}
+	return keys, nil
+}
+
+func (u BeerRepository) lockBeerStyle(ctx context.Context, tx ksql.Provider, beerUUID string) (domain.BeerStyle, error) {
+	var beerStyle domain.BeerStyle
+	err := tx.QueryOne(ctx, &beerStyle, u.lockBeerStyleQuery(), beerUUID)
+	return beerStyle, err
+}
+
+// PurgeDeletedBeerStyles permanently removes styles soft-deleted longer than
+// retention ago and returns how many were removed.
+func (u BeerRepository) PurgeDeletedBeerStyles(ctx context.Context, retention time.Duration) (int64, error) {
+	ctx, cancel := u.withTimeout(ctx)
+	defer cancel()
+
+	result, err := u.db.Exec(ctx, u.purgeDeletedBeerStylesQuery(), retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func translateError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NewNotFoundError("beer style not found")
+	}
+	return err
+}
+
+// translateNameConflict turns a unique violation into a domain conflict. The
+// index is what guarantees unique names; there is no check-then-write race.
+func translateNameConflict(err error, name string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return domain.NewConflictError("beer style with name '%s' already exists", name)
+	}
+	return err
+}
+
+func (u BeerRepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if u.queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, u.queryTimeout)
+}
+
 func (BeerRepository) getAllBeerStylesQuery() string {
 	return `
-		SELECT uuid, name, temp_min, temp_max, created_at, updated_at
+		SELECT uuid, name, temp_min, temp_max, created_at, updated_at, deleted_at, version
+		FROM beer_styles
+		WHERE deleted_at IS NULL
+	`
+}
+
+func (BeerRepository) listBeerStylesQuery(params domain.BeerStyleListParams) (string, []interface{}) {
+	column, ok := beerStyleSortColumns[params.SortBy]
+	if !ok {
+		column = "name"
+	}
+
+	direction, comparison := "ASC", ">"
+	if params.SortDir == "desc" {
+		direction, comparison = "DESC", "<"
+	}
+
+	var conditions []string
+	var args []interface{}
+
+	if !params.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	if params.Name != "" {
+		args = append(args, escapeLikePattern(params.Name))
+		conditions = append(conditions, fmt.Sprintf("name ILIKE '%%' || $%d || '%%'", len(args)))
+	}
+
+	if params.Temperature != nil {
+		args = append(args, *params.Temperature)
+		conditions = append(conditions, fmt.Sprintf("temp_min <= $%d AND temp_max >= $%d", len(args), len(args)))
+	}
+
+	if params.After != nil {
+		args = append(args, params.After.Value, params.After.UUID)
+		conditions = append(conditions, fmt.Sprintf("(%s, uuid) %s ($%d::%s, $%d::uuid)",
+			column, comparison, len(args)-1, beerStyleCursorCasts[params.SortBy], len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, params.Limit)
+
+	return fmt.Sprintf(`
+		SELECT uuid, name, temp_min, temp_max, created_at, updated_at, deleted_at, version
 		FROM beer_styles
+		%s
+		ORDER BY %s %s, uuid %s
+		LIMIT $%d
+	`, where, column, direction, direction, len(args)), args
+}
+
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+func (BeerRepository) beerStyleNameKeysQuery() string {
+	return `
+		SELECT DISTINCT names.name, beer_style_name_key(names.name) AS name_key
+		FROM unnest($1::text[]) AS names(name)
 	`
 }
 
 func (BeerRepository) getBeerStyleByUUIDQuery() string {
 	return `
-		SELECT uuid, name, temp_min, temp_max, created_at, updated_at
+		SELECT uuid, name, temp_min, temp_max, created_at, updated_at, deleted_at, version
+		FROM beer_styles
+		WHERE uuid = $1 AND deleted_at IS NULL
+	`
+}
+
+func (BeerRepository) getBeerStyleByUUIDIncludingDeletedQuery() string {
+	return `
+		SELECT uuid, name, temp_min, temp_max, created_at, updated_at, deleted_at, version
 		FROM beer_styles
 		WHERE uuid = $1
 	`
 }
 
+func (BeerRepository) lockBeerStyleQuery() string {
+	return `
+		SELECT uuid, name, temp_min, temp_max, created_at, updated_at, deleted_at, version
+		FROM beer_styles
+		WHERE uuid = $1
+		FOR UPDATE
+	`
+}
+
 func (BeerRepository) createBeerStyleQuery() string {
 	return `
 		INSERT INTO beer_styles (name, temp_min, temp_max)
 		VALUES ($1, $2, $3)
-		RETURNING uuid, name, temp_min, temp_max, created_at, updated_at;
+		RETURNING uuid, name, temp_min, temp_max, created_at, updated_at, deleted_at, version;
 	`
 }
 
@@ -113,15 +386,39 @@ func (BeerRepository) updateBeerStyleQuery() string {
 		SET name = $1,
 		temp_min = $2,
 		temp_max = $3,
-		updated_at = NOW()
-		WHERE uuid = $4
-		RETURNING uuid, name, temp_min, temp_max, created_at, updated_at;
+		updated_at = NOW(),
+		version = version + 1
+		WHERE uuid = $4 AND deleted_at IS NULL AND version = $5
+		RETURNING uuid, name, temp_min, temp_max, created_at, updated_at, deleted_at, version;
 	`
 }
 
 func (BeerRepository) deleteBeerStyleQuery() string {
+	return `
+		UPDATE beer_styles
+		SET deleted_at = NOW(),
+		updated_at = NOW(),
+		version = version + 1
+		WHERE uuid = $1 AND deleted_at IS NULL
+		RETURNING uuid, name, temp_min, temp_max, created_at, updated_at, deleted_at, version;
+	`
+}
+
+func (BeerRepository) restoreBeerStyleQuery() string {
+	return `
+		UPDATE beer_styles
+		SET deleted_at = NULL,
+		updated_at = NOW(),
+		version = version + 1
+		WHERE uuid = $1 AND deleted_at IS NOT NULL
+		RETURNING uuid, name, temp_min, temp_max, created_at, updated_at, deleted_at, version;
+	`
+}
+
+func (BeerRepository) purgeDeletedBeerStylesQuery() string {
 	return `
 		DELETE FROM beer_styles
-		WHERE uuid = $1;
+		WHERE deleted_at IS NOT NULL
+		AND deleted_at < NOW() - make_interval(secs => $1);
 	`
 }
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"backend-test/internal/domain"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/vingarcia/ksql"
+)
+
+func TestBeerRepository_NameUniqueViolationIsConflict(t *testing.T) {
+	current := domain.BeerStyle{UUID: "uuid-1", Name: "Marzen", TempMin: 4.0, TempMax: 7.0, Version: 2}
+
+	db := ksql.Mock{
+		QueryOneFn: func(ctx context.Context, record interface{}, query string, params ...interface{}) error {
+			if strings.Contains(query, "FOR UPDATE") {
+				*record.(*domain.BeerStyle) = current
+				return nil
+			}
+			return &pgconn.PgError{Code: uniqueViolation, ConstraintName: "beer_styles_name_key_active_key"}
+		},
+		ExecFn: (&auditRecorder{}).exec,
+	}
+	repo := NewBeerRepository(db, 0)
+	ctx := context.Background()
+
+	writes := map[string]func() error{
+		"create": func() error {
+			_, err := repo.CreateBeerStyle(ctx, domain.BeerStyle{Name: "MÄRZEN", TempMin: 4.0, TempMax: 7.0})
+			return err
+		},
+		"update": func() error {
+			_, err := repo.UpdateBeerStyle(ctx, domain.BeerStyle{UUID: "uuid-1", Name: "märzen", TempMin: 4.0, TempMax: 7.0, Version: 2})
+			return err
+		},
+		"restore": func() error {
+			_, err := repo.RestoreBeerStyle(ctx, "uuid-1")
+			return err
+		},
+	}
+
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			if err := write(); !errors.Is(err, domain.ErrConflict) {
+				t.Errorf("Expected conflict error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBeerRepository_BeerStyleNameKeys(t *testing.T) {
+	queries := 0
+	db := ksql.Mock{
+		QueryFn: func(ctx context.Context, records interface{}, query string, params ...interface{}) error {
+			queries++
+			if !strings.Contains(query, "beer_style_name_key(") {
+				t.Errorf("Expected the index's key function in the query, got %q", query)
+			}
+			names := params[0].([]string)
+			rows := records.(*[]beerStyleNameKeyRow)
+			for _, name := range names {
+				*rows = append(*rows, beerStyleNameKeyRow{Name: name, Key: "key:" + name})
+			}
+			return nil
+		},
+	}
+	repo := NewBeerRepository(db, 0)
+
+	keys, err := repo.BeerStyleNameKeys(context.Background(), []string{"Øl", "Märzen"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(keys) != 2 || keys["Øl"] != "key:Øl" || keys["Märzen"] != "key:Märzen" {
+		t.Errorf("Expected the keys computed by the database, got %v", keys)
+	}
+
+	if keys, err := repo.BeerStyleNameKeys(context.Background(), nil); err != nil || len(keys) != 0 {
+		t.Errorf("Expected no keys for no names, got %v, %v", keys, err)
+	}
+	if queries != 1 {
+		t.Errorf("Expected 1 query, got %d", queries)
+	}
+}
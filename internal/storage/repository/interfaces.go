@@ -1,11 +1,28 @@
 package repository
 
-import "backend-test/internal/domain"
+import (
+	"backend-test/internal/domain"
+	"context"
+	"time"
+)
 
 type BeerRepositoryInterface interface {
-	ListAllBeerStyles() ([]domain.BeerStyle, error)
-	GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle, error)
-	CreateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error)
-	UpdateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error)
-	DeleteBeerStyle(beerUUID string) error
+	ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error)
+	ListBeerStyles(ctx context.Context, params domain.BeerStyleListParams) ([]domain.BeerStyle, error)
+	GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error)
+	GetBeerStyleByUUIDIncludingDeleted(ctx context.Context, beerUUID string) (domain.BeerStyle, error)
+	CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error)
+	UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error)
+	DeleteBeerStyle(ctx context.Context, beerUUID string) error
+	RestoreBeerStyle(ctx context.Context, beerUUID string) (domain.BeerStyle, error)
+	PurgeDeletedBeerStyles(ctx context.Context, retention time.Duration) (int64, error)
+	BeerStyleNameKeys(ctx context.Context, names []string) (map[string]string, error)
+	ListBeerStyleHistory(ctx context.Context, params domain.BeerStyleHistoryParams) ([]domain.BeerStyleAuditEntry, error)
+	ListBeerStylePlaylists(ctx context.Context, beerUUID string) ([]domain.BeerStylePlaylist, error)
+	AddBeerStylePlaylist(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error)
+	UpdateBeerStylePlaylist(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error)
+	RemoveBeerStylePlaylist(ctx context.Context, beerUUID, playlistID string) error
+	StreamBeerStyles(ctx context.Context, chunkSize int, fn func([]domain.BeerStyle) error) error
+	WithTransaction(ctx context.Context, fn func(tx BeerRepositoryInterface) error) error
+	WithSavepoint(ctx context.Context, fn func() error) error
 }
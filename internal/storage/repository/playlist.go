@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"backend-test/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgconn"
+)
+
+// foreignKeyViolation is the Postgres SQLSTATE raised when a playlist is
+// attached to a beer style that does not exist.
+const foreignKeyViolation = "23503"
+
+func (u BeerRepository) ListBeerStylePlaylists(ctx context.Context, beerUUID string) ([]domain.BeerStylePlaylist, error) {
+	ctx, cancel := u.withTimeout(ctx)
+	defer cancel()
+
+	var playlists []domain.BeerStylePlaylist
+	if err := u.db.Query(ctx, &playlists, u.listBeerStylePlaylistsQuery(), beerUUID); err != nil {
+		return nil, err
+	}
+
+	return playlists, nil
+}
+
+func (u BeerRepository) AddBeerStylePlaylist(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error) {
+	ctx, cancel := u.withTimeout(ctx)
+	defer cancel()
+
+	var added domain.BeerStylePlaylist
+	err := u.db.QueryOne(ctx, &added, u.addBeerStylePlaylistQuery(), playlist.BeerStyleUUID, playlist.PlaylistID, playlist.Priority)
+	if err != nil {
+		return domain.BeerStylePlaylist{}, translatePlaylistError(err, playlist.PlaylistID)
+	}
+
+	return added, nil
+}
+
+func (u BeerRepository) UpdateBeerStylePlaylist(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error) {
+	ctx, cancel := u.withTimeout(ctx)
+	defer cancel()
+
+	var updated domain.BeerStylePlaylist
+	err := u.db.QueryOne(ctx, &updated, u.updateBeerStylePlaylistQuery(), playlist.BeerStyleUUID, playlist.PlaylistID, playlist.Priority)
+	if err != nil {
+		return domain.BeerStylePlaylist{}, translatePlaylistError(err, playlist.PlaylistID)
+	}
+
+	return updated, nil
+}
+
+func (u BeerRepository) RemoveBeerStylePlaylist(ctx context.Context, beerUUID, playlistID string) error {
+	ctx, cancel := u.withTimeout(ctx)
+	defer cancel()
+
+	result, err := u.db.Exec(ctx, u.removeBeerStylePlaylistQuery(), beerUUID, playlistID)
+	if err != nil {
+		return err
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return domain.NewNotFoundError("playlist '%s' is not attached to this beer style", playlistID)
+	}
+	return nil
+}
+
+func translatePlaylistError(err error, playlistID string) error {
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.NewNotFoundError("playlist '%s' is not attached to this beer style", playlistID)
+	case errors.As(err, &pgErr) && pgErr.Code == uniqueViolation:
+		return domain.NewConflictError("playlist '%s' is already attached to this beer style", playlistID)
+	case errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation:
+		return domain.NewNotFoundError("beer style not found")
+	}
+	return err
+}
+
+func (BeerRepository) listBeerStylePlaylistsQuery() string {
+	return `
+		SELECT beer_style_uuid, playlist_id, priority, created_at, updated_at
+		FROM beer_style_playlists
+		WHERE beer_style_uuid = $1
+		ORDER BY priority DESC, created_at, playlist_id
+	`
+}
+
+func (BeerRepository) addBeerStylePlaylistQuery() string {
+	return `
+		INSERT INTO beer_style_playlists (beer_style_uuid, playlist_id, priority)
+		VALUES ($1, $2, $3)
+		RETURNING beer_style_uuid, playlist_id, priority, created_at, updated_at;
+	`
+}
+
+func (BeerRepository) updateBeerStylePlaylistQuery() string {
+	return `
+		UPDATE beer_style_playlists
+		SET priority = $3,
+		updated_at = NOW()
+		WHERE beer_style_uuid = $1 AND playlist_id = $2
+		RETURNING beer_style_uuid, playlist_id, priority, created_at, updated_at;
+	`
+}
+
+func (BeerRepository) removeBeerStylePlaylistQuery() string {
+	return `
+		DELETE FROM beer_style_playlists
+		WHERE beer_style_uuid = $1 AND playlist_id = $2;
+	`
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"backend-test/internal/domain"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/vingarcia/ksql"
+)
+
+func TestBeerRepository_BeerStylePlaylistErrors(t *testing.T) {
+	playlist := domain.BeerStylePlaylist{BeerStyleUUID: "uuid-1", PlaylistID: "abc", Priority: 1}
+
+	tests := []struct {
+		name     string
+		db       ksql.Mock
+		write    func(repo *BeerRepository) error
+		expected error
+	}{
+		{
+			"attaching twice is a conflict",
+			ksql.Mock{QueryOneFn: func(ctx context.Context, record interface{}, query string, params ...interface{}) error {
+				return &pgconn.PgError{Code: uniqueViolation, ConstraintName: "beer_style_playlists_pkey"}
+			}},
+			func(repo *BeerRepository) error {
+				_, err := repo.AddBeerStylePlaylist(context.Background(), playlist)
+				return err
+			},
+			domain.ErrConflict,
+		},
+		{
+			"attaching to a missing style is not found",
+			ksql.Mock{QueryOneFn: func(ctx context.Context, record interface{}, query string, params ...interface{}) error {
+				return &pgconn.PgError{Code: foreignKeyViolation}
+			}},
+			func(repo *BeerRepository) error {
+				_, err := repo.AddBeerStylePlaylist(context.Background(), playlist)
+				return err
+			},
+			domain.ErrNotFound,
+		},
+		{
+			"updating a detached playlist is not found",
+			ksql.Mock{QueryOneFn: func(ctx context.Context, record interface{}, query string, params ...interface{}) error {
+				return ksql.ErrRecordNotFound
+			}},
+			func(repo *BeerRepository) error {
+				_, err := repo.UpdateBeerStylePlaylist(context.Background(), playlist)
+				return err
+			},
+			domain.ErrNotFound,
+		},
+		{
+			"removing a detached playlist is not found",
+			ksql.Mock{ExecFn: func(ctx context.Context, query string, params ...interface{}) (ksql.Result, error) {
+				return ksql.NewMockResult(0, 0), nil
+			}},
+			func(repo *BeerRepository) error {
+				return repo.RemoveBeerStylePlaylist(context.Background(), "uuid-1", "abc")
+			},
+			domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.write(NewBeerRepository(tt.db, 0)); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
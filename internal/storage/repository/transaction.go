@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vingarcia/ksql"
+)
+
+const (
+	savepointQuery         = "SAVEPOINT beer_style_item"
+	rollbackSavepointQuery = "ROLLBACK TO SAVEPOINT beer_style_item"
+	releaseSavepointQuery  = "RELEASE SAVEPOINT beer_style_item"
+)
+
+// WithTransaction runs fn with a repository bound to a single transaction.
+// Its methods join that transaction instead of opening their own, so every
+// write made through it commits or rolls back together.
+func (u BeerRepository) WithTransaction(ctx context.Context, fn func(tx BeerRepositoryInterface) error) error {
+	return u.db.Transaction(ctx, func(tx ksql.Provider) error {
+		txRepo := u
+		txRepo.db = tx
+		return fn(txRepo)
+	})
+}
+
+// WithSavepoint runs fn inside a savepoint and rolls back to it when fn
+// fails, keeping the rest of the transaction usable. It is only meaningful
+// on a repository handed out by WithTransaction.
+func (u BeerRepository) WithSavepoint(ctx context.Context, fn func() error) error {
+	if _, err := u.db.Exec(ctx, savepointQuery); err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		if _, rollbackErr := u.db.Exec(ctx, rollbackSavepointQuery); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+
+	_, err := u.db.Exec(ctx, releaseSavepointQuery)
+	return err
+}
@@ -1,16 +1,53 @@
 package main
 
 import (
-	_ "backend-test/internal/cmd/server"
-	"backend-test/internal/http/handler"
+	"backend-test/internal/app"
+	"backend-test/internal/cmd/migrate"
+	config "backend-test/internal/cmd/server"
 	"backend-test/internal/http/router"
-	postgres "backend-test/internal/storage/database"
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
-	defer postgres.CloseDB()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate.Run(ctx, cfg, os.Args[2:], os.Stdout); err != nil {
+			stop()
+			log.Fatalf("migrate: %v", err)
+		}
+		return
+	}
+
+	application, err := app.New(ctx, cfg)
+	if err != nil {
+		log.Fatalf("failed to start application: %v", err)
+	}
 
 	r := router.NewRouter()
-	handler.HandleRequests(r)
-	r.Run(":1111")
+	application.HandleRequests(r)
+
+	exitCode := 0
+	if err := application.Serve(ctx, r); err != nil {
+		log.Printf("server error: %v", err)
+		exitCode = 1
+	}
+
+	if err := application.Close(); err != nil {
+		log.Printf("failed to release resources: %v", err)
+		exitCode = 1
+	}
+
+	log.Println("Server stopped")
+	os.Exit(exitCode)
 }
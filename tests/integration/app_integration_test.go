@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"backend-test/internal/app"
+	config "backend-test/internal/cmd/server"
+	"backend-test/internal/domain"
+	"backend-test/internal/http/router"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vingarcia/ksql"
+)
+
+func TestApp_BootsRealStackWithInjectedDatabase(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := ksql.Mock{
+		QueryFn: func(ctx context.Context, records interface{}, query string, params ...interface{}) error {
+			beerStyles := records.(*[]domain.BeerStyle)
+			*beerStyles = []domain.BeerStyle{{UUID: "uuid-1", Name: "IPA", TempMin: 7.0, TempMax: 10.0}}
+			return nil
+		},
+	}
+
+	application, err := app.New(context.Background(), config.Config{}, app.WithDatabase(db))
+	if err != nil {
+		t.Fatalf("Failed to build application: %v", err)
+	}
+	defer application.Close()
+
+	r := router.NewRouter()
+	application.HandleRequests(r)
+
+	req, _ := http.NewRequest("GET", "/api/check", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	req, _ = http.NewRequest("GET", "/api/beer-styles/list", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response domain.BeerStylePage
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if len(response.BeerStyles) != 1 || response.BeerStyles[0].Name != "IPA" {
+		t.Errorf("Expected the beer style returned by the injected database, got %+v", response.BeerStyles)
+	}
+}
+
+func TestApp_ServeStopsWhenContextIsCancelled(t *testing.T) {
+	cfg := config.Config{Port: "127.0.0.1:0", ShutdownTimeout: time.Second}
+
+	application, err := app.New(context.Background(), cfg, app.WithDatabase(ksql.Mock{}))
+	if err != nil {
+		t.Fatalf("Failed to build application: %v", err)
+	}
+	defer application.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- application.Serve(ctx, router.NewRouter())
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected graceful shutdown, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve did not return after context cancellation")
+	}
+}
+
+func TestApp_ReportsMusicProviderStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		cfg           config.Config
+		expectedState string
+	}{
+		{
+			name:          "fake provider is always ready",
+			cfg:           config.Config{MusicProvider: config.MusicProviderFake},
+			expectedState: domain.MusicProviderStateReady,
+		},
+		{
+			name:          "spotify without credentials is disabled",
+			cfg:           config.Config{MusicProvider: config.MusicProviderSpotify},
+			expectedState: domain.MusicProviderStateDisabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			application, err := app.New(context.Background(), tt.cfg, app.WithDatabase(ksql.Mock{}))
+			if err != nil {
+				t.Fatalf("Failed to build application: %v", err)
+			}
+			defer application.Close()
+
+			r := router.NewRouter()
+			application.HandleRequests(r)
+
+			req, _ := http.NewRequest("GET", "/api/recommendations/provider/status", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+			}
+
+			var status domain.MusicProviderStatus
+			if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+
+			if status.State != tt.expectedState {
+				t.Errorf("Expected state %q, got %q", tt.expectedState, status.State)
+			}
+		})
+	}
+}
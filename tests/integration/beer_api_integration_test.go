@@ -5,6 +5,7 @@ import (
 	"backend-test/internal/http/controller"
 	"backend-test/internal/http/router"
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -18,16 +19,60 @@ type MockBeerService struct {
 	beers       []domain.BeerStyle
 	shouldError bool
 	errorMsg    string
+	// writeErr is returned by creates and updates, like the repository does
+	// for a name conflict.
+	writeErr error
 }
 
-func (m *MockBeerService) ListAllBeerStyles() ([]domain.BeerStyle, error) {
+func (m *MockBeerService) ListAllBeerStyles(ctx context.Context) ([]domain.BeerStyle, error) {
 	if m.shouldError {
 		return nil, &MockError{message: m.errorMsg}
 	}
 	return m.beers, nil
 }
 
-func (m *MockBeerService) GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle, error) {
+func (m *MockBeerService) ListBeerStyles(ctx context.Context, params domain.BeerStyleListParams) (domain.BeerStylePage, error) {
+	if m.shouldError {
+		return domain.BeerStylePage{}, &MockError{message: m.errorMsg}
+	}
+	return domain.BeerStylePage{
+		BeerStyles: m.beers,
+		Paging:     domain.Paging{Limit: params.Limit, Sort: params.SortBy, Order: params.SortDir},
+	}, nil
+}
+
+func (m *MockBeerService) GetBeerStyleByUUID(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	if m.shouldError {
+		return domain.BeerStyle{}, &MockError{message: m.errorMsg}
+	}
+	for _, beer := range m.beers {
+		if beer.UUID == beerUUID && beer.DeletedAt == nil {
+			return beer, nil
+		}
+	}
+	return domain.BeerStyle{}, domain.NewNotFoundError("beer style not found")
+}
+
+func (m *MockBeerService) ExportBeerStyles(ctx context.Context, fn func([]domain.BeerStyle) error) error {
+	if m.shouldError {
+		return &MockError{message: m.errorMsg}
+	}
+	return fn(m.beers)
+}
+
+func (m *MockBeerService) ApplyBeerStyleBulk(ctx context.Context, request domain.BeerStyleBulkRequest, rejected []error) (domain.BeerStyleBulkResult, error) {
+	if m.shouldError {
+		return domain.BeerStyleBulkResult{}, &MockError{message: m.errorMsg}
+	}
+	result := domain.BeerStyleBulkResult{Mode: request.Mode, Committed: true}
+	for i, operation := range request.Operations {
+		result.Results = append(result.Results, domain.BeerStyleBulkItemResult{Index: i, Op: operation.Op, Status: domain.BulkItemSucceeded})
+		result.Succeeded++
+	}
+	return result, nil
+}
+
+func (m *MockBeerService) GetBeerStyleByUUIDIncludingDeleted(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
 	if m.shouldError {
 		return domain.BeerStyle{}, &MockError{message: m.errorMsg}
 	}
@@ -36,13 +81,16 @@ func (m *MockBeerService) GetBeerStyleByUUID(beerUUID string) (domain.BeerStyle,
 			return beer, nil
 		}
 	}
-	return domain.BeerStyle{}, &MockError{message: "beer not found"}
+	return domain.BeerStyle{}, domain.NewNotFoundError("beer style not found")
 }
 
-func (m *MockBeerService) CreateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+func (m *MockBeerService) CreateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
 	if m.shouldError {
 		return domain.BeerStyle{}, &MockError{message: m.errorMsg}
 	}
+	if m.writeErr != nil {
+		return domain.BeerStyle{}, m.writeErr
+	}
 	beerStyle.UUID = "test-uuid-123"
 	beerStyle.CreatedAt = time.Now()
 	beerStyle.UpdatedAt = time.Now()
@@ -50,14 +98,86 @@ func (m *MockBeerService) CreateBeerStyle(beerStyle domain.BeerStyle) (domain.Be
 	return beerStyle, nil
 }
 
-func (m *MockBeerService) UpdateBeerStyle(beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
+func (m *MockBeerService) UpdateBeerStyle(ctx context.Context, beerStyle domain.BeerStyle) (domain.BeerStyle, error) {
 	if m.shouldError {
 		return domain.BeerStyle{}, &MockError{message: m.errorMsg}
 	}
+	if m.writeErr != nil {
+		return domain.BeerStyle{}, m.writeErr
+	}
 	return beerStyle, nil
 }
 
-func (m *MockBeerService) DeleteBeerStyle(beerUUID string) error {
+func (m *MockBeerService) DeleteBeerStyle(ctx context.Context, beerUUID string) error {
+	if m.shouldError {
+		return &MockError{message: m.errorMsg}
+	}
+	return nil
+}
+
+func (m *MockBeerService) ListBeerStyleHistory(ctx context.Context, params domain.BeerStyleHistoryParams) (domain.BeerStyleHistoryPage, error) {
+	if m.shouldError {
+		return domain.BeerStyleHistoryPage{}, &MockError{message: m.errorMsg}
+	}
+	return domain.BeerStyleHistoryPage{
+		History: []domain.BeerStyleAuditEntry{},
+		Paging:  domain.Paging{Limit: params.Limit, Sort: "created_at", Order: "desc"},
+	}, nil
+}
+
+func (m *MockBeerService) RestoreBeerStyle(ctx context.Context, beerUUID string) (domain.BeerStyle, error) {
+	if m.shouldError {
+		return domain.BeerStyle{}, &MockError{message: m.errorMsg}
+	}
+	for _, beer := range m.beers {
+		if beer.UUID == beerUUID {
+			beer.DeletedAt = nil
+			return beer, nil
+		}
+	}
+	return domain.BeerStyle{}, domain.NewNotFoundError("beer style not found")
+}
+
+func (m *MockBeerService) BeerStyleNameKeys(ctx context.Context, names []string) (map[string]string, error) {
+	if m.shouldError {
+		return nil, &MockError{message: m.errorMsg}
+	}
+	keys := make(map[string]string, len(names))
+	for _, name := range names {
+		keys[name] = domain.FoldBeerStyleName(name)
+	}
+	return keys, nil
+}
+
+func (m *MockBeerService) ListBeerStylePlaylists(ctx context.Context, beerUUID string) ([]domain.BeerStylePlaylist, error) {
+	if m.shouldError {
+		return nil, &MockError{message: m.errorMsg}
+	}
+	return []domain.BeerStylePlaylist{}, nil
+}
+
+func (m *MockBeerService) CuratedPlaylists(ctx context.Context, beerUUID string) ([]domain.BeerStylePlaylist, error) {
+	if m.shouldError {
+		return nil, &MockError{message: m.errorMsg}
+	}
+	return []domain.BeerStylePlaylist{}, nil
+}
+
+func (m *MockBeerService) AddBeerStylePlaylist(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error) {
+	if m.shouldError {
+		return domain.BeerStylePlaylist{}, &MockError{message: m.errorMsg}
+	}
+	return playlist, nil
+}
+
+func (m *MockBeerService) UpdateBeerStylePlaylist(ctx context.Context, playlist domain.BeerStylePlaylist) (domain.BeerStylePlaylist, error) {
+	if m.shouldError {
+		return domain.BeerStylePlaylist{}, &MockError{message: m.errorMsg}
+	}
+	return playlist, nil
+}
+
+func (m *MockBeerService) RemoveBeerStylePlaylist(ctx context.Context, beerUUID, playlistID string) error {
 	if m.shouldError {
 		return &MockError{message: m.errorMsg}
 	}
@@ -65,10 +185,9 @@ func (m *MockBeerService) DeleteBeerStyle(beerUUID string) error {
 }
 
 type MockValidationService struct {
-	shouldError     bool
-	errorMsg        string
-	uniqueNameError bool
-	tempRangeError  bool
+	shouldError    bool
+	errorMsg       string
+	tempRangeError bool
 }
 
 func (m *MockValidationService) ValidateTemperatureRange(beerStyle domain.BeerStyle) error {
@@ -80,29 +199,25 @@ func (m *MockValidationService) ValidateTemperatureRange(beerStyle domain.BeerSt
 
 func (m *MockValidationService) ValidateTemperatureInput(temperature float64) error {
 	if m.shouldError {
-		return &MockError{message: m.errorMsg}
+		return domain.NewValidationError("%s", m.errorMsg)
 	}
 	return nil
 }
 
-func (m *MockValidationService) ValidateUniqueNameForCreate(name string) error {
-	if m.uniqueNameError {
-		return &MockError{message: m.errorMsg}
+func (m *MockValidationService) ValidateWeatherQuery(query domain.WeatherQuery) error {
+	if m.shouldError {
+		return domain.NewValidationError("%s", m.errorMsg)
 	}
 	return nil
 }
 
-func (m *MockValidationService) ValidateUniqueNameForUpdate(name string, excludeUUID string) error {
+func (m *MockValidationService) ValidateListParams(params domain.BeerStyleListParams) error {
 	if m.shouldError {
 		return &MockError{message: m.errorMsg}
 	}
 	return nil
 }
 
-func (m *MockValidationService) IsNoRowsError(err error) bool {
-	return false
-}
-
 func (m *MockValidationService) ValidateUUID(uuidStr string) error {
 	if m.shouldError {
 		return &MockError{message: m.errorMsg}
@@ -110,6 +225,17 @@ func (m *MockValidationService) ValidateUUID(uuidStr string) error {
 	return nil
 }
 
+func (m *MockValidationService) ValidateBulkOperations(ctx context.Context, operations []domain.BeerStyleBulkOperation) ([]error, error) {
+	if m.shouldError {
+		return nil, &MockError{message: m.errorMsg}
+	}
+	return make([]error, len(operations)), nil
+}
+
+func (m *MockValidationService) ValidateBulkOperationsAgainst(beerStyles []domain.BeerStyle, keys map[string]string, operations []domain.BeerStyleBulkOperation) []error {
+	return make([]error, len(operations))
+}
+
 type MockUpdateService struct {
 	shouldError bool
 	errorMsg    string
@@ -119,6 +245,20 @@ func (m *MockUpdateService) ApplyBeerStyleUpdates(current *domain.BeerStyle, upd
 	return true
 }
 
+func (m *MockUpdateService) ApplyMergePatch(current domain.BeerStyle, patch []byte) (domain.BeerStyle, error) {
+	if m.shouldError {
+		return current, &MockError{message: m.errorMsg}
+	}
+	return current, nil
+}
+
+func (m *MockUpdateService) ApplyJSONPatch(current domain.BeerStyle, patch []byte) (domain.BeerStyle, error) {
+	if m.shouldError {
+		return current, &MockError{message: m.errorMsg}
+	}
+	return current, nil
+}
+
 type MockError struct {
 	message string
 }
@@ -139,6 +279,7 @@ func setupTestRouter(beerController *controller.BeerController) *gin.Engine {
 	beer.GET("/list", beerController.ListAllBeerStyles)
 	beer.POST("/create", beerController.CreateBeerStyle)
 	beer.PUT("/edit/:beerUUID", beerController.UpdateBeerStyle)
+	beer.GET("/:beerUUID", beerController.GetBeerStyle)
 	beer.DELETE("/:beerUUID", beerController.DeleteBeerStyle)
 
 	return r
@@ -169,9 +310,8 @@ func TestBeerAPI_ListAllBeerStyles_Success(t *testing.T) {
 		shouldError: false,
 	}
 	mockValidationService := &MockValidationService{
-		shouldError:     false,
-		uniqueNameError: false,
-		tempRangeError:  false,
+		shouldError:    false,
+		tempRangeError: false,
 	}
 	mockUpdateService := &MockUpdateService{shouldError: false}
 
@@ -214,9 +354,8 @@ func TestBeerAPI_ListAllBeerStyles_DatabaseError(t *testing.T) {
 		errorMsg:    "database connection failed",
 	}
 	mockValidationService := &MockValidationService{
-		shouldError:     false,
-		uniqueNameError: false,
-		tempRangeError:  false,
+		shouldError:    false,
+		tempRangeError: false,
 	}
 	mockUpdateService := &MockUpdateService{shouldError: false}
 
@@ -248,9 +387,8 @@ func TestBeerAPI_ListAllBeerStyles_EmptyResult(t *testing.T) {
 		shouldError: false,
 	}
 	mockValidationService := &MockValidationService{
-		shouldError:     false,
-		uniqueNameError: false,
-		tempRangeError:  false,
+		shouldError:    false,
+		tempRangeError: false,
 	}
 	mockUpdateService := &MockUpdateService{shouldError: false}
 
@@ -278,15 +416,105 @@ func TestBeerAPI_ListAllBeerStyles_EmptyResult(t *testing.T) {
 	}
 }
 
+func TestBeerAPI_ListAllBeerStyles_PagingParams(t *testing.T) {
+	mockBeerService := &MockBeerService{
+		beers:       []domain.BeerStyle{{UUID: "uuid-1", Name: "IPA", TempMin: 5.0, TempMax: 8.0}},
+		shouldError: false,
+	}
+	mockValidationService := &MockValidationService{
+		shouldError:    false,
+		tempRangeError: false,
+	}
+	mockUpdateService := &MockUpdateService{shouldError: false}
+
+	beerController := setupTestController(mockBeerService, mockValidationService, mockUpdateService)
+	testRouter := setupTestRouter(beerController)
+
+	req, _ := http.NewRequest("GET", "/api/beer-styles/list?limit=10&sort=created_at&order=desc&name=ip&temperature=6", nil)
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response domain.BeerStylePage
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Paging.Limit != 10 {
+		t.Errorf("Expected limit 10, got %d", response.Paging.Limit)
+	}
+
+	if response.Paging.Sort != "created_at" || response.Paging.Order != "desc" {
+		t.Errorf("Expected sort 'created_at desc', got '%s %s'", response.Paging.Sort, response.Paging.Order)
+	}
+
+	req, _ = http.NewRequest("GET", "/api/beer-styles/list?cursor=not-a-cursor", nil)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for invalid cursor, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestBeerAPI_GetBeerStyle_ConditionalRequest(t *testing.T) {
+	mockBeerService := &MockBeerService{
+		beers: []domain.BeerStyle{
+			{
+				UUID:      "uuid-1",
+				Name:      "IPA",
+				TempMin:   5.0,
+				TempMax:   8.0,
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			},
+		},
+		shouldError: false,
+	}
+	mockValidationService := &MockValidationService{
+		shouldError:    false,
+		tempRangeError: false,
+	}
+	mockUpdateService := &MockUpdateService{shouldError: false}
+
+	beerController := setupTestController(mockBeerService, mockValidationService, mockUpdateService)
+	testRouter := setupTestRouter(beerController)
+
+	req, _ := http.NewRequest("GET", "/api/beer-styles/uuid-1", nil)
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected ETag header to be set")
+	}
+
+	req, _ = http.NewRequest("GET", "/api/beer-styles/uuid-1", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotModified, w.Code)
+	}
+}
+
 func TestBeerAPI_CreateBeerStyle_Success(t *testing.T) {
 	mockBeerService := &MockBeerService{
 		beers:       []domain.BeerStyle{},
 		shouldError: false,
 	}
 	mockValidationService := &MockValidationService{
-		shouldError:     false,
-		uniqueNameError: false,
-		tempRangeError:  false,
+		shouldError:    false,
+		tempRangeError: false,
 	}
 	mockUpdateService := &MockUpdateService{shouldError: false}
 
@@ -333,10 +561,9 @@ func TestBeerAPI_CreateBeerStyle_ValidationError(t *testing.T) {
 		shouldError: false,
 	}
 	mockValidationService := &MockValidationService{
-		shouldError:     false,
-		uniqueNameError: false,
-		tempRangeError:  true,
-		errorMsg:        "temperature range invalid",
+		shouldError:    false,
+		tempRangeError: true,
+		errorMsg:       "temperature range invalid",
 	}
 	mockUpdateService := &MockUpdateService{shouldError: false}
 
@@ -374,9 +601,8 @@ func TestBeerAPI_CreateBeerStyle_ValidationError(t *testing.T) {
 func TestBeerAPI_CreateBeerStyle_InvalidJSON(t *testing.T) {
 	mockBeerService := &MockBeerService{beers: []domain.BeerStyle{}, shouldError: false}
 	mockValidationService := &MockValidationService{
-		shouldError:     false,
-		uniqueNameError: false,
-		tempRangeError:  false,
+		shouldError:    false,
+		tempRangeError: false,
 	}
 	mockUpdateService := &MockUpdateService{shouldError: false}
 
@@ -395,3 +621,50 @@ func TestBeerAPI_CreateBeerStyle_InvalidJSON(t *testing.T) {
 		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
 	}
 }
+
+func TestBeerAPI_NameConflict(t *testing.T) {
+	conflict := domain.NewConflictError("beer style with name 'IPA' already exists")
+	mockBeerService := &MockBeerService{
+		beers:    []domain.BeerStyle{{UUID: "0f8fad5b-d9cb-469f-a165-70867728950e", Name: "Lager", TempMin: -2.0, TempMax: 2.0}},
+		writeErr: conflict,
+	}
+	mockValidationService := &MockValidationService{}
+	mockUpdateService := &MockUpdateService{}
+
+	beerController := setupTestController(mockBeerService, mockValidationService, mockUpdateService)
+	testRouter := setupTestRouter(beerController)
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		ifMatch string
+	}{
+		{"create", "POST", "/api/beer-styles/create", ""},
+		{"update", "PUT", "/api/beer-styles/edit/0f8fad5b-d9cb-469f-a165-70867728950e", "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(`{"name": "ipa", "temp_min": 5.0, "temp_max": 8.0}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			testRouter.ServeHTTP(w, req)
+
+			if w.Code != http.StatusConflict {
+				t.Errorf("Expected status code %d, got %d", http.StatusConflict, w.Code)
+			}
+
+			var response map[string]string
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if response["message"] != conflict.Error() {
+				t.Errorf("Expected message '%s', got '%s'", conflict.Error(), response["message"])
+			}
+		})
+	}
+}
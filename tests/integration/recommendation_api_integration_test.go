@@ -5,7 +5,9 @@ import (
 	"backend-test/internal/http/controller"
 	"backend-test/internal/service"
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -16,10 +18,24 @@ import (
 type MockRecommendationService struct {
 	shouldError bool
 	errorMsg    string
+	err         error
 	response    domain.RecommendationResponse
 }
 
-func (m *MockRecommendationService) GetRecommendationForTemperature(temperature float64) (*domain.RecommendationResponse, error) {
+func (m *MockRecommendationService) GetRecommendationForTemperature(ctx context.Context, temperature float64, options domain.RecommendationOptions) (*domain.RecommendationResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.shouldError {
+		return nil, &MockError{message: m.errorMsg}
+	}
+	return &m.response, nil
+}
+
+func (m *MockRecommendationService) GetRecommendationForLocation(ctx context.Context, query domain.WeatherQuery, options domain.RecommendationOptions) (*domain.RecommendationResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
 	if m.shouldError {
 		return nil, &MockError{message: m.errorMsg}
 	}
@@ -70,15 +86,14 @@ func TestRecommendationAPI_SuggestSpotifyPlaylist_Success(t *testing.T) {
 	}
 
 	mockValidationService := &MockValidationService{
-		shouldError:     false,
-		uniqueNameError: false,
-		tempRangeError:  false,
+		shouldError:    false,
+		tempRangeError: false,
 	}
 
 	testRouter := setupRecommendationTestRouter(mockRecommendationService, mockValidationService)
 
 	requestBody := domain.TemperatureRequest{
-		Temperature: 6.0,
+		Temperature: ptrFloat64(6.0),
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -118,9 +133,8 @@ func TestRecommendationAPI_SuggestSpotifyPlaylist_Success(t *testing.T) {
 func TestRecommendationAPI_SuggestSpotifyPlaylist_InvalidJSON(t *testing.T) {
 	mockRecommendationService := &MockRecommendationService{shouldError: false}
 	mockValidationService := &MockValidationService{
-		shouldError:     false,
-		uniqueNameError: false,
-		tempRangeError:  false,
+		shouldError:    false,
+		tempRangeError: false,
 	}
 
 	testRouter := setupRecommendationTestRouter(mockRecommendationService, mockValidationService)
@@ -151,16 +165,15 @@ func TestRecommendationAPI_SuggestSpotifyPlaylist_InvalidJSON(t *testing.T) {
 func TestRecommendationAPI_SuggestSpotifyPlaylist_ValidationError(t *testing.T) {
 	mockRecommendationService := &MockRecommendationService{shouldError: false}
 	mockValidationService := &MockValidationService{
-		shouldError:     true,
-		uniqueNameError: false,
-		tempRangeError:  false,
-		errorMsg:        "temperature must be between -10 and 50 degrees",
+		shouldError:    true,
+		tempRangeError: false,
+		errorMsg:       "temperature must be between -10 and 50 degrees",
 	}
 
 	testRouter := setupRecommendationTestRouter(mockRecommendationService, mockValidationService)
 
 	requestBody := domain.TemperatureRequest{
-		Temperature: 100.0, // Invalid temperature
+		Temperature: ptrFloat64(100.0), // Invalid temperature
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -187,19 +200,17 @@ func TestRecommendationAPI_SuggestSpotifyPlaylist_ValidationError(t *testing.T)
 
 func TestRecommendationAPI_SuggestSpotifyPlaylist_NoPlaylistFound(t *testing.T) {
 	mockRecommendationService := &MockRecommendationService{
-		shouldError: true,
-		errorMsg:    "no playlist found for temperature",
+		err: domain.NewNotFoundError("no playlist found for temperature"),
 	}
 	mockValidationService := &MockValidationService{
-		shouldError:     false,
-		uniqueNameError: false,
-		tempRangeError:  false,
+		shouldError:    false,
+		tempRangeError: false,
 	}
 
 	testRouter := setupRecommendationTestRouter(mockRecommendationService, mockValidationService)
 
 	requestBody := domain.TemperatureRequest{
-		Temperature: 15.0,
+		Temperature: ptrFloat64(15.0),
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -226,19 +237,17 @@ func TestRecommendationAPI_SuggestSpotifyPlaylist_NoPlaylistFound(t *testing.T)
 
 func TestRecommendationAPI_SuggestSpotifyPlaylist_SpotifyUnavailable(t *testing.T) {
 	mockRecommendationService := &MockRecommendationService{
-		shouldError: true,
-		errorMsg:    "spotify service unavailable",
+		err: domain.NewUpstreamUnavailableError(nil, "music service is temporarily unavailable"),
 	}
 	mockValidationService := &MockValidationService{
-		shouldError:     false,
-		uniqueNameError: false,
-		tempRangeError:  false,
+		shouldError:    false,
+		tempRangeError: false,
 	}
 
 	testRouter := setupRecommendationTestRouter(mockRecommendationService, mockValidationService)
 
 	requestBody := domain.TemperatureRequest{
-		Temperature: 6.0,
+		Temperature: ptrFloat64(6.0),
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -258,26 +267,24 @@ func TestRecommendationAPI_SuggestSpotifyPlaylist_SpotifyUnavailable(t *testing.
 		t.Fatalf("Failed to unmarshal response: %v", err)
 	}
 
-	if response["message"] != "Spotify service is temporarily unavailable" {
-		t.Errorf("Expected message 'Spotify service is temporarily unavailable', got '%s'", response["message"])
+	if response["message"] != "music service is temporarily unavailable" {
+		t.Errorf("Expected message 'music service is temporarily unavailable', got '%s'", response["message"])
 	}
 }
 
 func TestRecommendationAPI_SuggestSpotifyPlaylist_BeerStyleError(t *testing.T) {
 	mockRecommendationService := &MockRecommendationService{
-		shouldError: true,
-		errorMsg:    "failed to find best beer style",
+		err: fmt.Errorf("%w: database unavailable", service.ErrBeerStyleSelection),
 	}
 	mockValidationService := &MockValidationService{
-		shouldError:     false,
-		uniqueNameError: false,
-		tempRangeError:  false,
+		shouldError:    false,
+		tempRangeError: false,
 	}
 
 	testRouter := setupRecommendationTestRouter(mockRecommendationService, mockValidationService)
 
 	requestBody := domain.TemperatureRequest{
-		Temperature: 6.0,
+		Temperature: ptrFloat64(6.0),
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -308,15 +315,14 @@ func TestRecommendationAPI_SuggestSpotifyPlaylist_GenericError(t *testing.T) {
 		errorMsg:    "unexpected database error",
 	}
 	mockValidationService := &MockValidationService{
-		shouldError:     false,
-		uniqueNameError: false,
-		tempRangeError:  false,
+		shouldError:    false,
+		tempRangeError: false,
 	}
 
 	testRouter := setupRecommendationTestRouter(mockRecommendationService, mockValidationService)
 
 	requestBody := domain.TemperatureRequest{
-		Temperature: 6.0,
+		Temperature: ptrFloat64(6.0),
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -340,3 +346,7 @@ func TestRecommendationAPI_SuggestSpotifyPlaylist_GenericError(t *testing.T) {
 		t.Errorf("Expected message 'Internal server error', got '%s'", response["message"])
 	}
 }
+
+func ptrFloat64(value float64) *float64 {
+	return &value
+}